@@ -0,0 +1,38 @@
+package http
+
+import (
+	"context"
+	"fmt"
+)
+
+func observeALPN(ex *Exchange) {
+	if ex.Response.TLS == nil {
+		return
+	}
+
+	protocol := ex.Response.TLS.NegotiatedProtocol
+	ex.Result.update(func(r *Result) {
+		r.ALPN = protocol
+	})
+}
+
+func assertALPN(ctx context.Context, ex *Exchange) error {
+	if ex.Config.ExpectALPN == "" {
+		return nil
+	}
+
+	if ex.Response.TLS == nil {
+		return fmt.Errorf("expect_alpn requires an https url")
+	}
+
+	protocol := ex.Response.TLS.NegotiatedProtocol
+	if protocol == "" {
+		return fmt.Errorf("expected ALPN protocol %q; server negotiated none", ex.Config.ExpectALPN)
+	}
+
+	if protocol != ex.Config.ExpectALPN {
+		return fmt.Errorf("expected ALPN protocol %q; got: %q", ex.Config.ExpectALPN, protocol)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Artifact is the evidence kept for one check: the full response together
+// with the outcome and the labels identifying the round and team.
+type Artifact struct {
+	Time    time.Time
+	Labels  map[string]string
+	URL     string
+	Outcome string
+	Status  string
+	Proto   string
+	Header  http.Header
+	Body    []byte
+}
+
+// ArtifactStore persists artifacts. Save errors never change a check's
+// outcome.
+type ArtifactStore interface {
+	Save(ctx context.Context, artifact Artifact) error
+}
+
+type labelsKey struct{}
+
+// WithLabels attaches labels such as round and team to ctx. They are merged
+// over the config's artifact_labels when an artifact is saved.
+func WithLabels(ctx context.Context, labels map[string]string) context.Context {
+	merged := map[string]string{}
+	if parent, ok := ctx.Value(labelsKey{}).(map[string]string); ok {
+		maps.Copy(merged, parent)
+	}
+	maps.Copy(merged, labels)
+
+	return context.WithValue(ctx, labelsKey{}, merged)
+}
+
+// DirStore writes each artifact to its own file in a directory.
+type DirStore string
+
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func (d DirStore) Save(ctx context.Context, artifact Artifact) error {
+	err := os.MkdirAll(string(d), 0o755)
+	if err != nil {
+		return err
+	}
+
+	name := artifact.Time.UTC().Format("20060102T150405.000000000Z")
+	keys := slices.Sorted(maps.Keys(artifact.Labels))
+	for _, key := range keys {
+		name += "_" + unsafeName.ReplaceAllString(key+"="+artifact.Labels[key], "_")
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "# time: %s\n", artifact.Time.UTC().Format(time.RFC3339Nano))
+	fmt.Fprintf(&out, "# url: %s\n", artifact.URL)
+	fmt.Fprintf(&out, "# outcome: %s\n", artifact.Outcome)
+	for _, key := range keys {
+		fmt.Fprintf(&out, "# label %s: %s\n", key, artifact.Labels[key])
+	}
+	fmt.Fprintf(&out, "%s %s\r\n", artifact.Proto, artifact.Status)
+	artifact.Header.Write(&out)
+	out.WriteString("\r\n")
+	out.Write(artifact.Body)
+
+	return os.WriteFile(filepath.Join(string(d), name+".http"), out.Bytes(), 0o644)
+}
+
+func parseLabels(raw string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, item := range splitList(raw) {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("artifact_labels must be \"key=value,key=value\" ; got: %v", raw)
+		}
+		labels[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return labels, nil
+}
+
+// saveArtifact records ex in the checker's store and the config's
+// artifact_dir, if either is set.
+func (c *Checker) saveArtifact(ctx context.Context, ex *Exchange, matchErr error) {
+	if c.artifacts == nil && ex.Config.ArtifactDir == "" {
+		return
+	}
+
+	labels, err := parseLabels(ex.Config.ArtifactLabels)
+	if err != nil {
+		labels = map[string]string{}
+	}
+	if extra, ok := ctx.Value(labelsKey{}).(map[string]string); ok {
+		maps.Copy(labels, extra)
+	}
+
+	outcome := "pass"
+	if matchErr != nil {
+		outcome = "fail: " + redact(ex.Config, matchErr.Error())
+	}
+
+	// The matcher may not have read the body; read whatever remains so the
+	// artifact is complete.
+	body, _ := ex.Body()
+
+	artifact := Artifact{
+		Time:    time.Now(),
+		Labels:  labels,
+		URL:     redact(ex.Config, ex.Request.URL.Redacted()),
+		Outcome: outcome,
+		Status:  ex.Response.Status,
+		Proto:   ex.Response.Proto,
+		Header:  ex.Response.Header.Clone(),
+		Body:    body,
+	}
+
+	if c.artifacts != nil {
+		_ = c.artifacts.Save(ctx, artifact)
+	}
+	if ex.Config.ArtifactDir != "" {
+		_ = DirStore(ex.Config.ArtifactDir).Save(ctx, artifact)
+	}
+}
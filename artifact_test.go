@@ -0,0 +1,64 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type captureArtifacts struct {
+	saved []Artifact
+}
+
+func (s *captureArtifacts) Save(ctx context.Context, artifact Artifact) error {
+	s.saved = append(s.saved, artifact)
+	return nil
+}
+
+func TestArtifactAndStateKeyRedacted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	target := strings.Replace(server.URL, "http://", "http://admin:hunter2@", 1) + "/?token=s3cr3t-token"
+	config, _ := json.Marshal(map[string]any{
+		"url":              target,
+		"expected_output":  "200",
+		"auth_token":       "s3cr3t-token",
+		"change_detection": "flag",
+	})
+
+	artifacts := &captureArtifacts{}
+	state := &MemoryStateStore{}
+	checker := New(WithArtifactStore(artifacts), WithStateStore(state))
+
+	err := checker.Run(context.Background(), string(config))
+	if err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+
+	if len(artifacts.saved) != 1 {
+		t.Fatalf("saved %d artifacts; want 1", len(artifacts.saved))
+	}
+	for _, secret := range []string{"hunter2", "s3cr3t-token"} {
+		if strings.Contains(artifacts.saved[0].URL, secret) {
+			t.Errorf("artifact URL %q contains %q", artifacts.saved[0].URL, secret)
+		}
+	}
+
+	conf, err := decodeConfig(string(config))
+	if err != nil {
+		t.Fatalf("decodeConfig() = %v", err)
+	}
+	key := "content:GET " + redact(conf, artifacts.saved[0].URL)
+	if _, seen, _ := state.Load(key); !seen {
+		t.Errorf("state has no entry under redacted key %q", key)
+	}
+	if _, seen, _ := state.Load("content:GET " + target); seen {
+		t.Errorf("state has an entry under the unredacted URL")
+	}
+}
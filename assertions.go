@@ -0,0 +1,52 @@
+package http
+
+import "context"
+
+// observations record facts about every primary exchange in the result,
+// whatever the match type. They run after the matcher so body timings are
+// complete.
+var observations = []func(ex *Exchange){
+	reportCertificate,
+	observeALPN,
+	observeTimings,
+	observeInterim,
+	observeStatus,
+	observeScore,
+}
+
+// assertions are response checks configured independently of match_type.
+// Each returns nil when its field is unset. They run in order after the
+// matcher passes.
+var assertions = []func(ctx context.Context, ex *Exchange) error{
+	assertALPN,
+	assertTLSHandshake,
+	assertIssuer,
+	assertFinalURL,
+	assertNoDowngrade,
+	assertContentType,
+	assertNegotiated,
+	assertVary,
+	assertAllowOrigin,
+	assertForbiddenStrings,
+	assertEarlyHints,
+	assertRequestID,
+	assertIdempotentReplay,
+	assertAssets,
+}
+
+func observe(ex *Exchange) {
+	for _, fn := range observations {
+		fn(ex)
+	}
+}
+
+func assert(ctx context.Context, ex *Exchange) error {
+	for _, fn := range assertions {
+		err := fn(ctx, ex)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// assetLinks returns the absolute URLs of the stylesheets, scripts and
+// images doc references, without duplicates.
+func assetLinks(base *url.URL, doc string) []string {
+	seen := map[string]bool{}
+	links := []string{}
+
+	for _, element := range scanHTML(doc) {
+		attr := ""
+		switch element.Tag {
+		case "link":
+			rel, _ := element.Attr("rel")
+			if !slices.Contains(strings.Fields(strings.ToLower(rel)), "stylesheet") {
+				continue
+			}
+			attr = "href"
+		case "script", "img":
+			attr = "src"
+		default:
+			continue
+		}
+
+		raw, ok := element.Attr(attr)
+		if !ok || strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		ref, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			continue
+		}
+
+		target := base.ResolveReference(ref)
+		target.Fragment = ""
+		if target.Scheme != "http" && target.Scheme != "https" {
+			continue
+		}
+
+		if seen[target.String()] {
+			continue
+		}
+		seen[target.String()] = true
+		links = append(links, target.String())
+	}
+
+	return links
+}
+
+// assertAssets fetches a random sample of asset_samples stylesheets, scripts
+// and images from an HTML response; each must return 200.
+func assertAssets(ctx context.Context, ex *Exchange) error {
+	if ex.Config.AssetSamples == 0 {
+		return nil
+	}
+
+	media := mediaType(ex.Response.Header.Get("Content-Type"))
+	if media != "text/html" && media != "application/xhtml+xml" {
+		return nil
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	links := assetLinks(ex.Response.Request.URL, string(body))
+	rand.Shuffle(len(links), func(i, j int) { links[i], links[j] = links[j], links[i] })
+	if len(links) > ex.Config.AssetSamples {
+		links = links[:ex.Config.AssetSamples]
+	}
+
+	failures := []string{}
+	for _, link := range links {
+		resp, err := ex.fetch(ctx, link)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", link, err))
+			continue
+		}
+		drainBody(resp.Body)
+
+		if resp.StatusCode != http.StatusOK {
+			failures = append(failures, fmt.Sprintf("%s: status %d", link, resp.StatusCode))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d sampled assets failed to load: %s", len(failures), len(links), strings.Join(failures, "; "))
+	}
+
+	return nil
+}
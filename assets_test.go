@@ -0,0 +1,78 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAssetLinks(t *testing.T) {
+	base, _ := url.Parse("https://example.com/app/")
+	page := `<link rel="stylesheet" href="site.css">
+<link rel="Alternate Stylesheet" href="/dark.css#v2">
+<link rel="icon" href="/favicon.ico">
+<script src="https://cdn.example.net/lib.js"></script>
+<script>inline()</script>
+<img src="/logo.png"><img src="/logo.png">
+<img src="data:image/png;base64,AAAA">
+<a href="/page">`
+
+	got := assetLinks(base, page)
+	want := []string{
+		"https://example.com/app/site.css",
+		"https://example.com/dark.css",
+		"https://cdn.example.net/lib.js",
+		"https://example.com/logo.png",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("assetLinks() = %q; want %q", got, want)
+	}
+}
+
+func TestAssetSamples(t *testing.T) {
+	page := `<link rel="stylesheet" href="/a.css"><script src="/b.js"></script><img src="/c.png">`
+
+	tests := []struct {
+		name        string
+		contentType string
+		broken      string
+		samples     int
+		fetches     int32
+		want        string
+	}{
+		{"all load", "text/html", "", 3, 3, ""},
+		{"sampled", "text/html", "", 2, 2, ""},
+		{"more samples than assets", "text/html", "", 10, 3, ""},
+		{"broken asset", "text/html", "/b.js", 3, 3, "1 of 3 sampled assets failed to load"},
+		{"not html", "text/plain", "/b.js", 3, 0, ""},
+		{"disabled", "text/html", "/b.js", 0, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetches atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/" {
+					w.Header().Set("Content-Type", tt.contentType)
+					w.Write([]byte(page))
+					return
+				}
+				fetches.Add(1)
+				if r.URL.Path == tt.broken {
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			err := runAgainst(t, server, map[string]any{"expected_output": "200", "asset_samples": tt.samples})
+			checkError(t, err, tt.want)
+
+			if got := fetches.Load(); got != tt.fetches {
+				t.Errorf("%d assets fetched; want %d", got, tt.fetches)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// AuthProvider attaches credentials to a request before it is sent.
+type AuthProvider interface {
+	Authenticate(ctx context.Context, conf Schema, req *http.Request) error
+}
+
+// ChallengeResponder may be implemented by an AuthProvider that needs to see
+// a 401 response before it can authenticate (e.g. digest or token refresh).
+// When it returns true the request is sent once more.
+type ChallengeResponder interface {
+	Challenge(ctx context.Context, conf Schema, req *http.Request, resp *http.Response) (bool, error)
+}
+
+// AuthProviderFunc adapts an ordinary function to an AuthProvider.
+type AuthProviderFunc func(ctx context.Context, conf Schema, req *http.Request) error
+
+func (f AuthProviderFunc) Authenticate(ctx context.Context, conf Schema, req *http.Request) error {
+	return f(ctx, conf, req)
+}
+
+var (
+	authMu    sync.RWMutex
+	providers = map[string]AuthProvider{}
+)
+
+// RegisterAuth makes p available as auth name. It panics if name is empty or
+// already registered.
+func RegisterAuth(name string, p AuthProvider) {
+	authMu.Lock()
+	defer authMu.Unlock()
+
+	if name == "" || p == nil {
+		panic("http: RegisterAuth called with empty name or nil provider")
+	}
+	if _, dup := providers[name]; dup {
+		panic("http: RegisterAuth called twice for " + name)
+	}
+
+	providers[name] = p
+}
+
+// AuthProviders returns the registered auth scheme names in sorted order.
+func AuthProviders() []string {
+	authMu.RLock()
+	defer authMu.RUnlock()
+
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func lookupAuth(name string) (AuthProvider, bool) {
+	authMu.RLock()
+	defer authMu.RUnlock()
+
+	p, ok := providers[name]
+	return p, ok
+}
+
+func authNone(ctx context.Context, conf Schema, req *http.Request) error {
+	return nil
+}
+
+func authBasic(ctx context.Context, conf Schema, req *http.Request) error {
+	req.SetBasicAuth(conf.AuthUsername, conf.AuthPassword)
+	return nil
+}
+
+func authBearer(ctx context.Context, conf Schema, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+conf.AuthToken)
+	return nil
+}
+
+func init() {
+	RegisterAuth("none", AuthProviderFunc(authNone))
+	RegisterAuth("basic", AuthProviderFunc(authBasic))
+	RegisterAuth("bearer", AuthProviderFunc(authBearer))
+	RegisterAuth("jwt", AuthProviderFunc(authJWT))
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// versionDetail spots banners that leak a version, e.g. "nginx/1.25.3" or
+// "Apache/2.4.58 (Ubuntu)".
+var versionDetail = regexp.MustCompile(`\d`)
+
+// serverBannerMatcher checks the Server header: under the hidden policy it
+// must be absent or free of version details, under the match policy it must
+// match the expected_output regex.
+type serverBannerMatcher struct{}
+
+func (serverBannerMatcher) ValidateConfig(conf Schema) error {
+	switch conf.BannerPolicy {
+	case "hidden":
+		return nil
+	case "match":
+		if conf.ExpectedOutput == "" {
+			return fmt.Errorf("expected_output must be provided; got: %v", conf.ExpectedOutput)
+		}
+
+		_, err := regexp.Compile(conf.ExpectedOutput)
+		if err != nil {
+			return fmt.Errorf("invalid regex pattern provided: %v; %q", conf.ExpectedOutput, err)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("invalid banner_policy provided: %v", conf.BannerPolicy)
+	}
+}
+
+func (serverBannerMatcher) Match(ctx context.Context, ex *Exchange) error {
+	banner := ex.Response.Header.Get("Server")
+
+	if ex.Config.BannerPolicy == "hidden" {
+		if versionDetail.MatchString(banner) {
+			return fmt.Errorf("Server header reveals version details: %q", banner)
+		}
+		return nil
+	}
+
+	pattern, err := regexp.Compile(ex.Config.ExpectedOutput)
+	if err != nil {
+		return fmt.Errorf("invalid regex pattern provided: %v; %q", ex.Config.ExpectedOutput, err)
+	}
+
+	if banner == "" {
+		return fmt.Errorf("response has no Server header")
+	}
+
+	if !pattern.MatchString(banner) {
+		return fmt.Errorf("Server header %q does not match %q", banner, ex.Config.ExpectedOutput)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// binaryMatcher compares the raw response body against expected_output,
+// which is base64 so binary downloads fit in a text config.
+type binaryMatcher struct{}
+
+func decodeExpectedBytes(expected string) ([]byte, error) {
+	// Long base64 blobs are often pasted wrapped across lines.
+	expected = strings.Join(strings.Fields(expected), "")
+
+	want, err := base64.StdEncoding.DecodeString(expected)
+	if err != nil {
+		return nil, fmt.Errorf("expected_output must be base64 for binaryMatch; %v", err)
+	}
+
+	return want, nil
+}
+
+func (binaryMatcher) ValidateConfig(conf Schema) error {
+	if conf.ExpectedOutput == "" {
+		return fmt.Errorf("expected_output must be provided; got: %v", conf.ExpectedOutput)
+	}
+
+	_, err := decodeExpectedBytes(conf.ExpectedOutput)
+	return err
+}
+
+func (binaryMatcher) Match(ctx context.Context, ex *Exchange) error {
+	want, err := decodeExpectedBytes(ex.Config.ExpectedOutput)
+	if err != nil {
+		return err
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(body, want) {
+		return nil
+	}
+
+	offset := 0
+	for offset < len(body) && offset < len(want) && body[offset] == want[offset] {
+		offset++
+	}
+
+	return fmt.Errorf("response body differs from expected bytes at offset %d; expected %d bytes, got: %d", offset, len(want), len(body))
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// idleTimeoutBody fails a body read once no data has arrived for timeout,
+// cancelling the request so a stalled connection is torn down instead of
+// blocking until the engine's deadline. Steady but slow streams never trip it.
+// The timer only runs while a Read is blocked, so time spent by a matcher
+// between reads is not counted.
+type idleTimeoutBody struct {
+	body    io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+	cancel  context.CancelFunc
+	expired atomic.Bool
+}
+
+func newIdleTimeoutBody(body io.ReadCloser, timeout time.Duration, cancel context.CancelFunc) *idleTimeoutBody {
+	b := &idleTimeoutBody{body: body, timeout: timeout, cancel: cancel}
+	b.timer = time.AfterFunc(timeout, func() {
+		b.expired.Store(true)
+		cancel()
+	})
+	b.timer.Stop()
+
+	return b
+}
+
+func (b *idleTimeoutBody) Read(p []byte) (int, error) {
+	if b.expired.Load() {
+		return 0, fmt.Errorf("response body stalled for more than %v", b.timeout)
+	}
+
+	b.timer.Reset(b.timeout)
+	n, err := b.body.Read(p)
+	b.timer.Stop()
+
+	if b.expired.Load() {
+		return n, fmt.Errorf("response body stalled for more than %v", b.timeout)
+	}
+
+	return n, err
+}
+
+func (b *idleTimeoutBody) Close() error {
+	b.timer.Stop()
+	defer b.cancel()
+
+	return b.body.Close()
+}
+
+// readLimitBody fails a body read once reads have waited for more than limit
+// in total, cancelling the request. It catches what idleTimeoutBody cannot: a
+// server that trickles a byte at a time, often enough never to look idle.
+// Like idleTimeoutBody, it only counts time spent blocked in Read.
+type readLimitBody struct {
+	body      io.ReadCloser
+	limit     time.Duration
+	remaining time.Duration
+	timer     *time.Timer
+	cancel    context.CancelFunc
+	expired   atomic.Bool
+}
+
+func newReadLimitBody(body io.ReadCloser, limit time.Duration, cancel context.CancelFunc) *readLimitBody {
+	b := &readLimitBody{body: body, limit: limit, remaining: limit, cancel: cancel}
+	b.timer = time.AfterFunc(limit, func() {
+		b.expired.Store(true)
+		cancel()
+	})
+	b.timer.Stop()
+
+	return b
+}
+
+func (b *readLimitBody) Read(p []byte) (int, error) {
+	if b.expired.Load() || b.remaining <= 0 {
+		return 0, fmt.Errorf("response body took more than %v to read", b.limit)
+	}
+
+	start := time.Now()
+	b.timer.Reset(b.remaining)
+	n, err := b.body.Read(p)
+	b.timer.Stop()
+	b.remaining -= time.Since(start)
+
+	if b.expired.Load() {
+		return n, fmt.Errorf("response body took more than %v to read", b.limit)
+	}
+
+	return n, err
+}
+
+func (b *readLimitBody) Close() error {
+	b.timer.Stop()
+	defer b.cancel()
+
+	return b.body.Close()
+}
+
+// withBodyTimeouts applies read_idle_timeout_ms and body_timeout_ms to body.
+// Either one cancels the request through cancel when it trips.
+func withBodyTimeouts(body io.ReadCloser, conf Schema, cancel context.CancelFunc) io.ReadCloser {
+	if conf.ReadIdleTimeoutMs > 0 {
+		body = newIdleTimeoutBody(body, time.Duration(conf.ReadIdleTimeoutMs)*time.Millisecond, cancel)
+	}
+	if conf.BodyTimeoutMs > 0 {
+		body = newReadLimitBody(body, time.Duration(conf.BodyTimeoutMs)*time.Millisecond, cancel)
+	}
+
+	return body
+}
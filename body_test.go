@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBodyTimeouts(t *testing.T) {
+	// Sends a byte every interval until the client goes away, then the marker
+	// if it is ever reached.
+	trickle := func(interval time.Duration, bytes int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			for i := 0; i < bytes; i++ {
+				w.Write([]byte("."))
+				w.(http.Flusher).Flush()
+				select {
+				case <-time.After(interval):
+				case <-r.Context().Done():
+					return
+				}
+			}
+			w.Write([]byte("done"))
+		}
+	}
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		idle    int
+		total   int
+		want    string
+	}{
+		{"steady stream", trickle(10*time.Millisecond, 10), 200, 0, ""},
+		{"stalled", trickle(time.Hour, 1), 50, 0, "response body stalled for more than 50ms"},
+		{"trickle outlasts idle timeout", trickle(10*time.Millisecond, 1000), 200, 100, "response body took more than 100ms to read"},
+		{"within total", trickle(10*time.Millisecond, 5), 200, 2000, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			err := runAgainst(t, server, map[string]any{
+				"match_type":           "substringMatch",
+				"expected_output":      "done",
+				"read_idle_timeout_ms": tt.idle,
+				"body_timeout_ms":      tt.total,
+			})
+			checkError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cacheRequirement is one comma-separated item of a cacheControl
+// expected_output: a directive that must be present, optionally with a value
+// comparison such as max-age>=3600, or with a leading ! must be absent.
+type cacheRequirement struct {
+	name   string
+	absent bool
+	op     string
+	value  string
+}
+
+func parseCacheRequirements(raw string) ([]cacheRequirement, error) {
+	requirements := []cacheRequirement{}
+
+	for _, item := range splitList(raw) {
+		req := cacheRequirement{}
+		if strings.HasPrefix(item, "!") {
+			req.absent = true
+			item = strings.TrimSpace(item[1:])
+		}
+
+		req.name = item
+		for _, op := range []string{">=", "<=", "=", ">", "<"} {
+			if name, value, ok := strings.Cut(item, op); ok {
+				req.name, req.op, req.value = strings.TrimSpace(name), op, strings.TrimSpace(value)
+				break
+			}
+		}
+		req.name = strings.ToLower(req.name)
+
+		if req.name == "" || (req.absent && req.op != "") {
+			return nil, fmt.Errorf("invalid Cache-Control requirement provided: %v", item)
+		}
+
+		if req.op != "" && req.op != "=" {
+			_, err := strconv.Atoi(req.value)
+			if err != nil {
+				return nil, fmt.Errorf("Cache-Control requirement %v must compare against a number", item)
+			}
+		}
+
+		requirements = append(requirements, req)
+	}
+
+	if len(requirements) == 0 {
+		return nil, fmt.Errorf("expected_output must list Cache-Control requirements such as \"no-store\" or \"max-age>=3600\"")
+	}
+
+	return requirements, nil
+}
+
+func (r cacheRequirement) check(directives map[string]string) error {
+	value, ok := directives[r.name]
+
+	if r.absent {
+		if ok {
+			return fmt.Errorf("Cache-Control must not contain %s", r.name)
+		}
+		return nil
+	}
+
+	if !ok {
+		return fmt.Errorf("Cache-Control does not contain %s", r.name)
+	}
+
+	if r.op == "" {
+		return nil
+	}
+
+	if r.op == "=" {
+		if value != r.value {
+			return fmt.Errorf("expected Cache-Control %s=%s; got: %s=%s", r.name, r.value, r.name, value)
+		}
+		return nil
+	}
+
+	have, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("Cache-Control %s has non-numeric value %q", r.name, value)
+	}
+	want, _ := strconv.Atoi(r.value)
+
+	ok = false
+	switch r.op {
+	case ">=":
+		ok = have >= want
+	case "<=":
+		ok = have <= want
+	case ">":
+		ok = have > want
+	case "<":
+		ok = have < want
+	}
+	if !ok {
+		return fmt.Errorf("expected Cache-Control %s%s%d; got: %d", r.name, r.op, want, have)
+	}
+
+	return nil
+}
+
+// cacheControlMatcher parses the response's Cache-Control directives and
+// checks each requirement listed in expected_output.
+type cacheControlMatcher struct{}
+
+func (cacheControlMatcher) ValidateConfig(conf Schema) error {
+	_, err := parseCacheRequirements(conf.ExpectedOutput)
+	return err
+}
+
+func (cacheControlMatcher) Match(ctx context.Context, ex *Exchange) error {
+	requirements, err := parseCacheRequirements(ex.Config.ExpectedOutput)
+	if err != nil {
+		return err
+	}
+
+	header := strings.Join(ex.Response.Header.Values("Cache-Control"), ",")
+
+	directives, err := parseDirectives(header, ",")
+	if err != nil {
+		return fmt.Errorf("invalid Cache-Control header: %v", err)
+	}
+
+	for _, requirement := range requirements {
+		err = requirement.check(directives)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseCacheRequirements(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []cacheRequirement
+		err  string
+	}{
+		{"no-store", []cacheRequirement{{name: "no-store"}}, ""},
+		{"Max-Age>=3600, !no-cache", []cacheRequirement{{name: "max-age", op: ">=", value: "3600"}, {name: "no-cache", absent: true}}, ""},
+		{"s-maxage<60", []cacheRequirement{{name: "s-maxage", op: "<", value: "60"}}, ""},
+		{"private=set-cookie", []cacheRequirement{{name: "private", op: "=", value: "set-cookie"}}, ""},
+		{"", nil, "expected_output must list Cache-Control requirements"},
+		{"!max-age>1", nil, "invalid Cache-Control requirement"},
+		{"max-age>=forever", nil, "must compare against a number"},
+		{"=1", nil, "invalid Cache-Control requirement"},
+		{" , ,", nil, "expected_output must list Cache-Control requirements"},
+		{"private=", []cacheRequirement{{name: "private", op: "=", value: ""}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseCacheRequirements(tt.raw)
+			checkError(t, err, tt.err)
+			if tt.err == "" && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCacheRequirements(%q) = %+v; want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheControlMatcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   []string
+		expected string
+		want     string
+	}{
+		{"present", []string{"no-store"}, "no-store", ""},
+		{"missing", []string{"public"}, "no-store", "Cache-Control does not contain no-store"},
+		{"absent", []string{"private"}, "!public", ""},
+		{"not absent", []string{"public, max-age=60"}, "!public", "must not contain public"},
+		{"at least", []string{"max-age=3600"}, "max-age>=3600", ""},
+		{"too short", []string{"max-age=60"}, "max-age>=3600", "expected Cache-Control max-age>=3600; got: 60"},
+		{"below", []string{"max-age=30"}, "max-age<60", ""},
+		{"equals", []string{`private="Set-Cookie"`}, "private=Set-Cookie", ""},
+		{"non-numeric", []string{"max-age=soon"}, "max-age>0", "non-numeric value"},
+		{"split across fields", []string{"public", "max-age=600"}, "public, max-age>=600", ""},
+		{"repeated", []string{"max-age=1, max-age=2"}, "max-age>0", "appears more than once"},
+		{"no header", nil, "!no-store", ""},
+		{"no header, required", nil, "no-store", "Cache-Control does not contain no-store"},
+		{"directive case", []string{"No-Store, Max-Age=0"}, "no-store, max-age<=0", ""},
+		{"at most exceeded", []string{"max-age=1"}, "max-age<=0", "expected Cache-Control max-age<=0; got: 1"},
+		{"value mismatch", []string{"private=set-cookie"}, "private=authorization", "expected Cache-Control private=authorization; got: private=set-cookie"},
+		{"first failure wins", []string{"public"}, "no-store, !public", "Cache-Control does not contain no-store"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for _, value := range tt.header {
+					w.Header().Add("Cache-Control", value)
+				}
+			}))
+			defer server.Close()
+
+			err := runAgainst(t, server, map[string]any{"match_type": "cacheControl", "expected_output": tt.expected})
+			checkError(t, err, tt.want)
+		})
+	}
+}
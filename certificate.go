@@ -0,0 +1,96 @@
+package http
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// reportCertificate verifies the server's chain out-of-band for checks that
+// run with insecure set, noting any problem in the result without failing.
+func reportCertificate(ex *Exchange) {
+	state := ex.Response.TLS
+	if !ex.Config.Insecure || state == nil || len(state.PeerCertificates) == 0 {
+		return
+	}
+
+	err := verifyChain(state.PeerCertificates, ex.Response.Request.URL.Hostname())
+	if err != nil {
+		ex.Result.Note("certificate not trusted (accepted because insecure is set): %v", err)
+		return
+	}
+
+	ex.Result.Note("certificate verified")
+}
+
+func verifyChain(chain []*x509.Certificate, host string) error {
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	leaf := chain[0]
+
+	_, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Intermediates: intermediates,
+	})
+	if err == nil {
+		return nil
+	}
+
+	var unknown x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+
+	switch {
+	case errors.As(err, &hostname):
+		return fmt.Errorf("hostname mismatch: certificate is not valid for %s", host)
+	case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+		if time.Now().Before(leaf.NotBefore) {
+			return fmt.Errorf("not yet valid: valid from %s", leaf.NotBefore.UTC().Format(time.RFC3339))
+		}
+		return fmt.Errorf("expired: valid until %s", leaf.NotAfter.UTC().Format(time.RFC3339))
+	case errors.As(err, &unknown):
+		if len(chain) == 1 && leaf.Subject.String() == leaf.Issuer.String() {
+			return fmt.Errorf("self-signed certificate for %s", leaf.Subject)
+		}
+		return fmt.Errorf("issued by unknown authority %s", leaf.Issuer)
+	default:
+		return err
+	}
+}
+
+// assertIssuer expects the leaf certificate's issuer DN, as in
+// CN=Competition CA,O=Scoring, to be expect_issuer or to match it in full as
+// a regex. The chain must have been verified, or the issuer proves nothing.
+func assertIssuer(ctx context.Context, ex *Exchange) error {
+	want := ex.Config.ExpectIssuer
+	if want == "" {
+		return nil
+	}
+
+	state := ex.Response.TLS
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return fmt.Errorf("expect_issuer requires an https url")
+	}
+
+	if len(state.VerifiedChains) == 0 {
+		return fmt.Errorf("expect_issuer requires a verified certificate chain; the chain was not verified")
+	}
+
+	issuer := state.PeerCertificates[0].Issuer.String()
+	if issuer == want {
+		return nil
+	}
+
+	pattern, err := regexp.Compile("^(?:" + want + ")$")
+	if err != nil || !pattern.MatchString(issuer) {
+		return fmt.Errorf("expected certificate issuer %s; got: %s", want, issuer)
+	}
+
+	return nil
+}
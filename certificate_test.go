@@ -0,0 +1,61 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAssertIssuer(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	insecure := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+
+	// httptest's certificate is self-signed by O=Acme Co.
+	tests := []struct {
+		name   string
+		issuer string
+		client *http.Client
+		want   string
+	}{
+		{"exact", "O=Acme Co", server.Client(), ""},
+		{"regex", "O=Acme.*", server.Client(), ""},
+		{"alternation", "O=Evil Corp|O=Acme Co", server.Client(), ""},
+		{"partial match", "Acme", server.Client(), "expected certificate issuer Acme"},
+		{"wrong issuer", "O=Evil Corp", server.Client(), "expected certificate issuer O=Evil Corp"},
+		{"unverified chain", "O=Acme Co", insecure, "chain was not verified"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, _ := json.Marshal(map[string]any{
+				"url":             server.URL,
+				"expected_output": "200",
+				"expect_issuer":   tt.issuer,
+			})
+
+			err := New(WithClient(tt.client)).Run(context.Background(), string(config))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Run() = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Run() = %v; want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateIssuerRequiresVerification(t *testing.T) {
+	err := Validate(`{"url": "https://example.com/", "expected_output": "200", "expect_issuer": "O=Acme Co", "insecure": true}`)
+	if err == nil || !strings.Contains(err.Error(), "expect_issuer requires insecure to be false") {
+		t.Fatalf("Validate() = %v; want insecure error", err)
+	}
+}
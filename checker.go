@@ -0,0 +1,98 @@
+package http
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"sync"
+)
+
+// Checker runs checks with a configurable HTTP stack. The zero value is not
+// usable; construct one with New.
+type Checker struct {
+	client    *http.Client
+	pool      *clientPool
+	artifacts ArtifactStore
+	state     StateStore
+	report    func(Report)
+
+	cursorsMu sync.Mutex
+	cursors   map[string]int
+}
+
+// Option customizes a Checker.
+type Option func(*Checker)
+
+// WithClient makes every check use client, ignoring transport-related config
+// fields such as insecure. Redirects are still limited by max_redirects in
+// place of client's CheckRedirect.
+func WithClient(client *http.Client) Option {
+	return func(c *Checker) {
+		c.client = client
+	}
+}
+
+// WithRoundTripper makes every check send requests through rt.
+func WithRoundTripper(rt http.RoundTripper) Option {
+	return func(c *Checker) {
+		c.client = &http.Client{Transport: rt}
+	}
+}
+
+// WithDialer makes the checker's transports open connections through d.
+func WithDialer(d Dialer) Option {
+	return func(c *Checker) {
+		c.pool = &clientPool{dialer: d}
+	}
+}
+
+// WithArtifactStore makes the checker save every response to store, in
+// addition to any artifact_dir set in the config.
+func WithArtifactStore(store ArtifactStore) Option {
+	return func(c *Checker) {
+		c.artifacts = store
+	}
+}
+
+// WithStateStore makes the checker keep change_detection state in store,
+// overriding any state_file in the config.
+func WithStateStore(store StateStore) Option {
+	return func(c *Checker) {
+		c.state = store
+	}
+}
+
+// New returns a Checker configured by opts. Without options it behaves like
+// the package-level Run.
+func New(opts ...Option) *Checker {
+	c := &Checker{pool: defaultPool}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+var defaultChecker = New()
+
+func (c *Checker) httpClient(conf Schema) *http.Client {
+	base := c.client
+	if base == nil {
+		base = c.pool.get(conf)
+	}
+
+	// A copy, so max_redirects applies to injected clients too without
+	// changing the caller's client.
+	limited := *base
+	limited.CheckRedirect = checkRedirect(conf.MaxRedirects)
+	client := &limited
+
+	if conf.CookieJar {
+		// A copy of the shared client, so the jar lives for this check only.
+		jar, _ := cookiejar.New(nil)
+		session := *client
+		session.Jar = jar
+		client = &session
+	}
+
+	return client
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWithClientMaxRedirects(t *testing.T) {
+	// Each request redirects to the next hop until hop 3.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hop, _ := strconv.Atoi(r.URL.Query().Get("hop"))
+		if hop < 3 {
+			http.Redirect(w, r, "/?hop="+strconv.Itoa(hop+1), http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		maxRedirects int
+		want         string
+	}{
+		{"within limit", 3, ""},
+		{"over limit", 1, "got: 302"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := server.Client()
+			config, _ := json.Marshal(map[string]any{
+				"url":             server.URL,
+				"expected_output": "200",
+				"max_redirects":   tt.maxRedirects,
+			})
+
+			err := New(WithClient(client)).Run(context.Background(), string(config))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Run() = %v; want nil", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Run() = %v; want error containing %q", err, tt.want)
+			}
+
+			if client.CheckRedirect != nil {
+				t.Errorf("the injected client was modified")
+			}
+		})
+	}
+}
+
+// runAgainst points conf's url at server, then validates and runs it with
+// server's client.
+func runAgainst(t *testing.T, server *httptest.Server, conf map[string]any) error {
+	t.Helper()
+
+	doc := map[string]any{"url": server.URL}
+	for key, value := range conf {
+		doc[key] = value
+	}
+	config, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+
+	err = Validate(string(config))
+	if err != nil {
+		return err
+	}
+
+	return New(WithClient(server.Client())).Run(context.Background(), string(config))
+}
+
+// checkError fails t unless err is nil when want is empty, or contains want.
+func checkError(t *testing.T, err error, want string) {
+	t.Helper()
+
+	if want == "" {
+		if err != nil {
+			t.Fatalf("got error %v; want nil", err)
+		}
+		return
+	}
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("got error %v; want error containing %q", err, want)
+	}
+}
@@ -0,0 +1,159 @@
+// Package checktest runs check configs against canned local servers so check
+// authors can regression-test their configs without a live target.
+package checktest
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	scorify "github.com/scorify/http"
+)
+
+// Status responds with code and body to every request.
+func Status(code int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+		fmt.Fprint(w, body)
+	})
+}
+
+// Delay waits d (or until the client gives up) before handing the request to
+// next.
+func Delay(d time.Duration, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(d):
+			next.ServeHTTP(w, r)
+		case <-r.Context().Done():
+		}
+	})
+}
+
+// Redirect answers with n successive 302s to the same path before handing the
+// request to next.
+func Redirect(n int, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hop, _ := strconv.Atoi(r.URL.Query().Get("hop"))
+		if hop >= n {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		target := *r.URL
+		query := target.Query()
+		query.Set("hop", strconv.Itoa(hop+1))
+		target.RawQuery = query.Encode()
+
+		http.Redirect(w, r, target.String(), http.StatusFound)
+	})
+}
+
+// NewServer starts a plain HTTP server for h. Callers must Close it.
+func NewServer(h http.Handler) *httptest.Server {
+	return httptest.NewServer(h)
+}
+
+// NewTLSServer starts an HTTPS server for h with a self-signed certificate.
+// A non-nil config replaces the server's TLS settings, which allows testing
+// against specific protocol versions or cipher suites. Configs run against it
+// need insecure set.
+func NewTLSServer(h http.Handler, config *tls.Config) *httptest.Server {
+	srv := httptest.NewUnstartedServer(h)
+	if config != nil {
+		srv.TLS = config
+	}
+	srv.StartTLS()
+
+	return srv
+}
+
+// urlKeys are the config keys that name URLs on the target.
+var urlKeys = []string{"url", "fallback_url", "download_url", "protected_url", "logout_url", "tunnel_url"}
+
+// Target rewrites every URL in config to point at srv, keeping its path and
+// query, so production configs can be run unmodified. Each line of a
+// multi-line url is rewritten, as are fallback_url, download_url,
+// protected_url, logout_url and tunnel_url.
+func Target(config string, srv *httptest.Server) (string, error) {
+	doc := map[string]any{}
+
+	err := json.Unmarshal([]byte(config), &doc)
+	if err != nil {
+		return "", fmt.Errorf("encountered error while parsing config: %v", err)
+	}
+
+	for _, key := range urlKeys {
+		raw, _ := doc[key].(string)
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		lines := strings.Split(raw, "\n")
+		for i, line := range lines {
+			if strings.TrimSpace(line) != "" {
+				lines[i] = retarget(line, srv.URL)
+			}
+		}
+		doc[key] = strings.Join(lines, "\n")
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("encountered error while encoding config: %v", err)
+	}
+
+	return string(out), nil
+}
+
+// retarget replaces the scheme and host of raw with those of server. It works
+// on the text rather than a parsed URL, since a templated host such as
+// https://{{.host}}/ does not parse; a relative URL, or one that starts with
+// a placeholder for its origin, gets server as its origin.
+func retarget(raw string, server string) string {
+	rest := strings.TrimSpace(raw)
+
+	if _, after, ok := strings.Cut(rest, "://"); ok {
+		rest = ""
+		if i := strings.IndexAny(after, "/?#"); i >= 0 {
+			rest = after[i:]
+		}
+	} else if strings.HasPrefix(rest, "{{") {
+		if i := strings.Index(rest, "}}"); i >= 0 {
+			rest = rest[i+2:]
+		}
+	}
+
+	if rest != "" && !strings.ContainsRune("/?#", rune(rest[0])) {
+		rest = "/" + rest
+	}
+
+	return server + rest
+}
+
+// Run points config at srv and runs it with a new Checker that has no
+// options, so it behaves like the package-level Run.
+func Run(ctx context.Context, srv *httptest.Server, config string) error {
+	return RunWith(ctx, scorify.New(), srv, config)
+}
+
+// RunWith points config at srv and runs it with checker.
+func RunWith(ctx context.Context, checker *scorify.Checker, srv *httptest.Server, config string) error {
+	config, err := Target(config, srv)
+	if err != nil {
+		return err
+	}
+
+	err = scorify.Validate(config)
+	if err != nil {
+		return err
+	}
+
+	return checker.Run(ctx, config)
+}
@@ -0,0 +1,125 @@
+package checktest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	scorify "github.com/scorify/http"
+)
+
+func TestTarget(t *testing.T) {
+	srv := NewServer(Status(http.StatusOK, "ok"))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		key  string
+		raw  string
+		want string
+	}{
+		{"url", "url", "https://prod.example.com:8443/health?full=1", srv.URL + "/health?full=1"},
+		{"no path", "url", "http://prod.example.com", srv.URL},
+		{"multi-line", "url", "http://a.example.com/one\nhttp://b.example.com/two", srv.URL + "/one\n" + srv.URL + "/two"},
+		{"templated host", "url", "http://{{.host}}/status/{{.team}}", srv.URL + "/status/{{.team}}"},
+		{"templated origin", "url", "{{.base}}/status", srv.URL + "/status"},
+		{"relative", "url", "/status", srv.URL + "/status"},
+		{"fallback_url", "fallback_url", "http://backup.example.com/", srv.URL + "/"},
+		{"download_url", "download_url", "http://files.example.com/f?id=1", srv.URL + "/f?id=1"},
+		{"protected_url", "protected_url", "http://app.example.com/account", srv.URL + "/account"},
+		{"logout_url", "logout_url", "http://app.example.com/logout", srv.URL + "/logout"},
+		{"tunnel_url", "tunnel_url", "http://inside.example.com/", srv.URL + "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, _ := json.Marshal(map[string]any{tt.key: tt.raw, "body": "http://untouched.example.com/"})
+
+			out, err := Target(string(config), srv)
+			if err != nil {
+				t.Fatalf("Target() = %v", err)
+			}
+
+			doc := map[string]any{}
+			json.Unmarshal([]byte(out), &doc)
+			if doc[tt.key] != tt.want {
+				t.Errorf("%s = %q; want %q", tt.key, doc[tt.key], tt.want)
+			}
+			if doc["body"] != "http://untouched.example.com/" {
+				t.Errorf("body = %q; want it untouched", doc["body"])
+			}
+		})
+	}
+}
+
+func TestTargetInvalidConfig(t *testing.T) {
+	srv := NewServer(Status(http.StatusOK, "ok"))
+	defer srv.Close()
+
+	_, err := Target("{", srv)
+	if err == nil || !strings.Contains(err.Error(), "encountered error while parsing config") {
+		t.Fatalf("Target() = %v; want parse error", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.Handler
+		config  string
+		want    string
+	}{
+		{"status", Status(http.StatusOK, "hello"), `{"url": "http://prod.example.com/", "expected_output": "200"}`, ""},
+		{"substring", Status(http.StatusOK, "hello world"), `{"url": "http://prod.example.com/", "match_type": "substringMatch", "expected_output": "world"}`, ""},
+		{"wrong status", Status(http.StatusInternalServerError, ""), `{"url": "http://prod.example.com/", "expected_output": "200"}`, "expected status code"},
+		{"redirects", Redirect(3, Status(http.StatusOK, "")), `{"url": "http://prod.example.com/start", "expected_output": "200"}`, ""},
+		{"too many redirects", Redirect(3, Status(http.StatusOK, "")), `{"url": "http://prod.example.com/start", "expected_output": "200", "max_redirects": 2}`, "got: 302"},
+		{"invalid config", Status(http.StatusOK, ""), `{"url": "http://prod.example.com/", "expected_output": "200", "verb": "FETCH"}`, "FETCH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := NewServer(tt.handler)
+			defer srv.Close()
+
+			err := Run(context.Background(), srv, tt.config)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Run() = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Run() = %v; want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunWithTLS(t *testing.T) {
+	srv := NewTLSServer(Status(http.StatusOK, ""), nil)
+	defer srv.Close()
+
+	checker := scorify.New(scorify.WithClient(srv.Client()))
+
+	err := RunWith(context.Background(), checker, srv, `{"url": "https://prod.example.com/", "expected_output": "200"}`)
+	if err != nil {
+		t.Fatalf("RunWith() = %v; want nil", err)
+	}
+}
+
+func TestRunDelay(t *testing.T) {
+	srv := NewServer(Delay(time.Second, Status(http.StatusOK, "")))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := Run(ctx, srv, `{"url": "http://prod.example.com/", "expected_output": "200"}`)
+	if err == nil {
+		t.Fatalf("Run() = nil; want the delayed response to time out")
+	}
+}
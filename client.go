@@ -0,0 +1,179 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Dialer opens network connections for a transport. *net.Dialer satisfies it.
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// transportKey identifies the settings that require a distinct transport.
+// Checks that agree on every field share one pool of connections.
+type transportKey struct {
+	insecure bool
+	http2    bool
+	fresh    bool
+	dialMap  string
+	family   string
+	eyeballs int
+	proxy    string
+	connect  int
+	header   int
+}
+
+func keyFor(conf Schema) transportKey {
+	return transportKey{
+		insecure: conf.Insecure,
+		http2:    conf.ExpectALPN != "",
+		fresh:    conf.MaxTLSHandshakeMs > 0,
+		dialMap:  conf.DialMap,
+		family:   conf.IPFamily,
+		eyeballs: conf.HappyEyeballsMs,
+		proxy:    conf.ProxyURL,
+		connect:  conf.ConnectTimeoutMs,
+		header:   conf.HeaderTimeoutMs,
+	}
+}
+
+// clientPool hands out one client per transportKey so connections and TLS
+// sessions are reused across rounds.
+type clientPool struct {
+	mu      sync.Mutex
+	clients map[transportKey]*http.Client
+	dialer  Dialer
+}
+
+var defaultPool = &clientPool{}
+
+func (p *clientPool) get(conf Schema) *http.Client {
+	key := keyFor(conf)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.clients == nil {
+		p.clients = map[transportKey]*http.Client{}
+	}
+
+	client, ok := p.clients[key]
+	if !ok {
+		client = &http.Client{Transport: p.newTransport(key)}
+		p.clients[key] = client
+	}
+
+	return client
+}
+
+func (p *clientPool) newTransport(key transportKey) *http.Transport {
+	transport := &http.Transport{
+		TLSClientConfig:     &tls.Config{InsecureSkipVerify: key.insecure},
+		MaxIdleConns:        1024,
+		MaxIdleConnsPerHost: 16,
+		IdleConnTimeout:     90 * time.Second,
+		ForceAttemptHTTP2:   key.http2,
+		DisableKeepAlives:   key.fresh,
+	}
+	transport.DialContext = p.dialerFor(key).DialContext
+
+	if key.proxy != "" {
+		// Validated before the check runs.
+		proxy, _ := url.Parse(key.proxy)
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if key.connect > 0 {
+		timeout := time.Duration(key.connect) * time.Millisecond
+		transport.TLSHandshakeTimeout = timeout
+
+		dial := transport.DialContext
+		transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return dial(ctx, network, address)
+		}
+	}
+
+	transport.ResponseHeaderTimeout = time.Duration(key.header) * time.Millisecond
+
+	return transport
+}
+
+// dialerFor returns the pool's dialer, or a plain one when it has none,
+// restricted to key's IP family and routed through its dial_map.
+func (p *clientPool) dialerFor(key transportKey) Dialer {
+	dialer := p.dialer
+	if dialer == nil {
+		dialer = &net.Dialer{
+			Timeout:       30 * time.Second,
+			KeepAlive:     30 * time.Second,
+			FallbackDelay: time.Duration(key.eyeballs) * time.Millisecond,
+		}
+	}
+
+	switch key.family {
+	case "ipv4":
+		dialer = familyDialer{Dialer: dialer, suffix: "4"}
+	case "ipv6":
+		dialer = familyDialer{Dialer: dialer, suffix: "6"}
+	}
+
+	return withDialMap(dialer, key.dialMap)
+}
+
+// familyDialer pins tcp dials to tcp4 or tcp6, so a target that publishes
+// both A and AAAA records is always reached over the same family.
+type familyDialer struct {
+	Dialer
+	suffix string
+}
+
+func (d familyDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if network == "tcp" {
+		network += d.suffix
+	}
+
+	return d.Dialer.DialContext(ctx, network, address)
+}
+
+// drainBody discards a bounded amount of unread body so the connection can go
+// back into the idle pool, then closes it.
+func drainBody(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, 64<<10))
+	body.Close()
+}
+
+// withServerName returns a copy of client that presents name as the TLS
+// server name (SNI) and verifies the certificate against it, whatever host
+// the request URL names. Its connections are not pooled. Only an
+// *http.Transport can be given a server name; other transports are an error.
+func withServerName(client *http.Client, name string) (*http.Client, error) {
+	transport, ok := client.Transport.(*http.Transport)
+	if client.Transport == nil {
+		transport, ok = http.DefaultTransport.(*http.Transport)
+	}
+	if !ok {
+		return nil, fmt.Errorf("cannot set the TLS server name on a client with transport %T", client.Transport)
+	}
+
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.ServerName = name
+	transport.DisableKeepAlives = true
+
+	named := *client
+	named.Transport = transport
+
+	return &named, nil
+}
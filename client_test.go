@@ -0,0 +1,104 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientPoolKey(t *testing.T) {
+	base := Schema{IPFamily: "any"}
+
+	tests := []struct {
+		name   string
+		change func(*Schema)
+		shared bool
+	}{
+		{"same settings", func(c *Schema) {}, true},
+		{"unrelated field", func(c *Schema) { c.ExpectedOutput = "404" }, true},
+		{"insecure", func(c *Schema) { c.Insecure = true }, false},
+		{"proxy", func(c *Schema) { c.ProxyURL = "http://proxy.internal:3128" }, false},
+		{"connect timeout", func(c *Schema) { c.ConnectTimeoutMs = 500 }, false},
+		{"header timeout", func(c *Schema) { c.HeaderTimeoutMs = 500 }, false},
+		{"dial map", func(c *Schema) { c.DialMap = "a:80=b:80" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := &clientPool{}
+			conf := base
+			tt.change(&conf)
+
+			shared := pool.get(base) == pool.get(conf)
+			if shared != tt.shared {
+				t.Errorf("shared client = %v; want %v", shared, tt.shared)
+			}
+		})
+	}
+}
+
+func TestProxyURL(t *testing.T) {
+	proxied := make(chan string, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied <- r.URL.String()
+		w.Write([]byte("via proxy"))
+	}))
+	defer proxy.Close()
+
+	config, _ := json.Marshal(map[string]any{
+		"url":             "http://target.invalid/status",
+		"proxy_url":       proxy.URL,
+		"match_type":      "substringMatch",
+		"expected_output": "via proxy",
+	})
+
+	err := Run(context.Background(), string(config))
+	if err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+	if got := <-proxied; got != "http://target.invalid/status" {
+		t.Errorf("proxy received %q; want the absolute target URL", got)
+	}
+}
+
+func TestHeaderTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	config, _ := json.Marshal(map[string]any{"url": server.URL, "expected_output": "200", "header_timeout_ms": 50})
+
+	err := Run(context.Background(), string(config))
+	if err == nil || !strings.Contains(err.Error(), "timeout awaiting response headers") {
+		t.Fatalf("Run() = %v; want a response header timeout", err)
+	}
+}
+
+func TestValidateTransportSettings(t *testing.T) {
+	tests := []struct {
+		field string
+		value any
+		want  string
+	}{
+		{"proxy_url", "socks5://proxy:1080", "proxy_url must be an http or https URL"},
+		{"proxy_url", "proxy:3128", "proxy_url must be an http or https URL"},
+		{"connect_timeout_ms", -1, "connect_timeout_ms must not be negative"},
+		{"header_timeout_ms", -1, "header_timeout_ms must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			config, _ := json.Marshal(map[string]any{"url": "http://example.com/", "expected_output": "200", tt.field: tt.value})
+
+			err := Validate(string(config))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Validate() = %v; want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// compressedMatcher expects the response body to arrive compressed and to
+// decode cleanly. A non-empty expected_output must appear in the decoded body.
+//
+// The transport asks for gzip on its own and decodes it transparently, which
+// Response.Uncompressed records; when the request sets Accept-Encoding
+// itself the body is decoded here instead.
+type compressedMatcher struct{}
+
+func (compressedMatcher) ValidateConfig(conf Schema) error {
+	return nil
+}
+
+func (compressedMatcher) Match(ctx context.Context, ex *Exchange) error {
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	if !ex.Response.Uncompressed {
+		encoding := strings.ToLower(strings.TrimSpace(ex.Response.Header.Get("Content-Encoding")))
+
+		body, err = decodeBody(encoding, body)
+		if err != nil {
+			return err
+		}
+	}
+
+	if ex.Config.ExpectedOutput != "" && !strings.Contains(string(body), ex.Config.ExpectedOutput) {
+		return fmt.Errorf("expected output not found in decoded response body")
+	}
+
+	return nil
+}
+
+func decodeBody(encoding string, body []byte) ([]byte, error) {
+	var reader io.Reader
+
+	switch encoding {
+	case "":
+		return nil, fmt.Errorf("response was not compressed; no Content-Encoding header")
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("encountered error while decoding gzip body: %v", err)
+		}
+		reader = gz
+	case "deflate":
+		zr, err := zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			// Some servers send raw deflate without the zlib wrapper.
+			reader = flate.NewReader(bytes.NewReader(body))
+		} else {
+			reader = zr
+		}
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding: %q", encoding)
+	}
+
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("encountered error while decoding %s body: %v", encoding, err)
+	}
+
+	return decoded, nil
+}
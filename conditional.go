@@ -0,0 +1,108 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// notModifiedMatcher revalidates the response with its own validators and
+// expects the server to answer 304 Not Modified.
+type notModifiedMatcher struct{}
+
+func (notModifiedMatcher) ValidateConfig(conf Schema) error {
+	if conf.Verb != "GET" && conf.Verb != "HEAD" {
+		return fmt.Errorf("notModified requires a GET or HEAD verb; got: %v", conf.Verb)
+	}
+
+	return nil
+}
+
+func (notModifiedMatcher) Match(ctx context.Context, ex *Exchange) error {
+	if ex.Response.StatusCode != http.StatusOK {
+		return fmt.Errorf("expected status code: %d; got: %d", http.StatusOK, ex.Response.StatusCode)
+	}
+
+	etag := ex.Response.Header.Get("ETag")
+	lastModified := ex.Response.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return fmt.Errorf("response has neither ETag nor Last-Modified header")
+	}
+
+	req, err := ex.NewRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	resp, err := ex.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("encounted error while making conditional request: %v", err.Error())
+	}
+	defer drainBody(resp.Body)
+
+	if resp.StatusCode != http.StatusNotModified {
+		return fmt.Errorf("expected status code on conditional request: %d; got: %d", http.StatusNotModified, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// etagMatcher fetches the resource a second time and expects its ETag to be
+// the same, or with etag_policy changes, to differ.
+type etagMatcher struct{}
+
+func (etagMatcher) ValidateConfig(conf Schema) error {
+	if conf.Verb != "GET" && conf.Verb != "HEAD" {
+		return fmt.Errorf("etag requires a GET or HEAD verb; got: %v", conf.Verb)
+	}
+
+	if conf.ETagPolicy != "stable" && conf.ETagPolicy != "changes" {
+		return fmt.Errorf("invalid etag_policy provided: %v", conf.ETagPolicy)
+	}
+
+	return nil
+}
+
+func (etagMatcher) Match(ctx context.Context, ex *Exchange) error {
+	if ex.Response.StatusCode != http.StatusOK {
+		return fmt.Errorf("expected status code: %d; got: %d", http.StatusOK, ex.Response.StatusCode)
+	}
+
+	first := ex.Response.Header.Get("ETag")
+	if first == "" {
+		return fmt.Errorf("response has no ETag header")
+	}
+
+	req, err := ex.NewRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := ex.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("encounted error while making second request: %v", err.Error())
+	}
+	defer drainBody(resp.Body)
+
+	second := resp.Header.Get("ETag")
+	if second == "" {
+		return fmt.Errorf("second response has no ETag header")
+	}
+
+	if ex.Config.ETagPolicy == "stable" && second != first {
+		return fmt.Errorf("expected a stable ETag; got %s then %s", first, second)
+	}
+
+	if ex.Config.ETagPolicy == "changes" && second == first {
+		return fmt.Errorf("expected the ETag to change; got %s twice", first)
+	}
+
+	return nil
+}
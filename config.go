@@ -0,0 +1,83 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfig reads a check config from a .json, .yaml or .yml file, validates
+// it, and returns it as the JSON config string accepted by Run and Validate.
+func LoadConfig(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("encountered error while reading config file: %v", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ParseJSONConfig(data)
+	case ".yaml", ".yml":
+		return ParseYAMLConfig(data)
+	default:
+		return "", fmt.Errorf("unsupported config file extension: %q", filepath.Ext(path))
+	}
+}
+
+// ParseJSONConfig validates a JSON config document and returns it in compact
+// form.
+func ParseJSONConfig(data []byte) (string, error) {
+	doc := map[string]any{}
+
+	err := json.Unmarshal(data, &doc)
+	if err != nil {
+		return "", fmt.Errorf("encountered error while parsing json config: %v", err)
+	}
+
+	return encodeConfig(doc)
+}
+
+// ParseYAMLConfig validates a YAML config document and returns the equivalent
+// JSON config string.
+func ParseYAMLConfig(data []byte) (string, error) {
+	doc := map[string]any{}
+
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return "", fmt.Errorf("encountered error while parsing yaml config: %v", err)
+	}
+
+	return encodeConfig(doc)
+}
+
+// encodeConfig converts doc's values to their field's kind, fills in the
+// keys it leaves out, and validates the result.
+func encodeConfig(doc map[string]any) (string, error) {
+	doc, err := coerceConfig(doc)
+	if err != nil {
+		return "", err
+	}
+
+	doc, err = withDefaults(doc)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("encountered error while encoding config: %v", err)
+	}
+
+	config := string(out)
+
+	err = Validate(config)
+	if err != nil {
+		return "", err
+	}
+
+	return config, nil
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseYAMLConfig(t *testing.T) {
+	config, err := ParseYAMLConfig([]byte("url: http://example.com/\nexpected_output: 200\nretries: \"2\"\ninsecure: true\n"))
+	if err != nil {
+		t.Fatalf("ParseYAMLConfig() = %v", err)
+	}
+
+	doc := map[string]any{}
+	_ = json.Unmarshal([]byte(config), &doc)
+
+	if doc["expected_output"] != "200" {
+		t.Errorf("expected_output = %#v; want \"200\"", doc["expected_output"])
+	}
+	if doc["retries"] != float64(2) {
+		t.Errorf("retries = %#v; want 2", doc["retries"])
+	}
+	if doc["verb"] != "GET" {
+		t.Errorf("verb = %#v; want default GET", doc["verb"])
+	}
+}
+
+func TestParseJSONConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"minimal", `{"url": "http://example.com/", "expected_output": "200"}`, ""},
+		{"number for string", `{"url": "http://example.com/", "expected_output": 200}`, ""},
+		{"string for bool", `{"url": "http://example.com/", "expected_output": "200", "insecure": "true"}`, ""},
+		{"object for string", `{"url": "http://example.com/", "expected_output": {"a": 1}}`, "invalid value for expected_output"},
+		{"word for int", `{"url": "http://example.com/", "expected_output": "200", "retries": "two"}`, "invalid value for retries"},
+		{"fraction for int", `{"url": "http://example.com/", "expected_output": "200", "retries": 2.5}`, "invalid value for retries"},
+		{"invalid config", `{"url": "http://example.com/", "expected_output": "abc"}`, "invalid status code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseJSONConfig([]byte(tt.data))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("ParseJSONConfig() = %v", err)
+				}
+				err = Validate(config)
+				if err != nil {
+					t.Fatalf("Validate(parsed) = %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("ParseJSONConfig() = %v; want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func validateConsecutive(conf Schema) error {
+	if conf.Consecutive < 1 {
+		return fmt.Errorf("consecutive_successes must be at least 1; got: %d", conf.Consecutive)
+	}
+
+	if conf.AttemptDelayMs < 0 {
+		return fmt.Errorf("attempt_delay_ms must not be negative; got: %d", conf.AttemptDelayMs)
+	}
+
+	return nil
+}
+
+// runConsecutive requires consecutive_successes attempts in a row to pass,
+// attempt_delay_ms apart, so a service that only flaps up briefly fails.
+func (c *Checker) runConsecutive(ctx context.Context, conf Schema, res *Result) error {
+	for i := 0; i < conf.Consecutive; i++ {
+		if i > 0 {
+			err := sleep(ctx, time.Duration(conf.AttemptDelayMs)*time.Millisecond)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := c.attempt(ctx, conf, res)
+		if err != nil {
+			return fmt.Errorf("attempt %d of %d consecutive failed: %v", i+1, conf.Consecutive, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConsecutive(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		required int
+		requests int32
+		want     string
+	}{
+		{"all pass", []int{200, 200, 200}, 3, 3, ""},
+		{"one required", []int{200, 500}, 1, 1, ""},
+		{"flaps down", []int{200, 500, 200}, 3, 2, "attempt 2 of 3 consecutive failed: expected status code: 200; got: 500"},
+		{"down first", []int{503, 200, 200}, 3, 1, "attempt 1 of 3 consecutive failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statuses[requests.Add(1)-1])
+			}))
+			defer server.Close()
+
+			err := runAgainst(t, server, map[string]any{
+				"mode":                  "consecutive",
+				"expected_output":       "200",
+				"consecutive_successes": tt.required,
+				"attempt_delay_ms":      0,
+			})
+			checkError(t, err, tt.want)
+
+			if got := requests.Load(); got != tt.requests {
+				t.Errorf("server saw %d requests; want %d", got, tt.requests)
+			}
+		})
+	}
+}
+
+func TestConsecutiveDelay(t *testing.T) {
+	var mu sync.Mutex
+	arrivals := []time.Time{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	err := runAgainst(t, server, map[string]any{
+		"mode":                  "consecutive",
+		"expected_output":       "200",
+		"consecutive_successes": 3,
+		"attempt_delay_ms":      40,
+	})
+	checkError(t, err, "")
+
+	for i := 1; i < len(arrivals); i++ {
+		if gap := arrivals[i].Sub(arrivals[i-1]); gap < 40*time.Millisecond {
+			t.Errorf("attempt %d followed the previous one after %v; want at least 40ms", i+1, gap)
+		}
+	}
+}
+
+func TestConsecutiveCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	config, _ := json.Marshal(map[string]any{
+		"url":                   server.URL,
+		"mode":                  "consecutive",
+		"expected_output":       "200",
+		"consecutive_successes": 2,
+		"attempt_delay_ms":      10000,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := New().Run(ctx, string(config))
+	checkError(t, err, "encounted error while waiting")
+}
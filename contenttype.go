@@ -0,0 +1,48 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"regexp"
+	"strings"
+)
+
+// mediaType returns the lower-cased media type of a Content-Type value
+// without parameters, or "" when there is none.
+func mediaType(contentType string) string {
+	media, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		media, _, _ = strings.Cut(contentType, ";")
+	}
+
+	return strings.ToLower(strings.TrimSpace(media))
+}
+
+// mediaTypeMatches compares a media type with want, first literally and then
+// as an anchored, case-insensitive regex.
+func mediaTypeMatches(media string, want string) bool {
+	if strings.EqualFold(media, want) {
+		return true
+	}
+
+	pattern, err := regexp.Compile("(?i)^(?:" + want + ")$")
+	return err == nil && pattern.MatchString(media)
+}
+
+func assertContentType(ctx context.Context, ex *Exchange) error {
+	if ex.Config.ExpectContentType == "" {
+		return nil
+	}
+
+	header := ex.Response.Header.Get("Content-Type")
+	if header == "" {
+		return fmt.Errorf("response has no Content-Type header; expected %s", ex.Config.ExpectContentType)
+	}
+
+	if !mediaTypeMatches(mediaType(header), ex.Config.ExpectContentType) {
+		return fmt.Errorf("expected Content-Type %s; got: %s", ex.Config.ExpectContentType, header)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMediaType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+	}{
+		{"application/json", "application/json"},
+		{"Application/JSON; charset=utf-8", "application/json"},
+		{" text/html ;charset=utf-8", "text/html"},
+		{"text/html; charset", "text/html"},
+		{"multipart/form-data; boundary=\"a;b\"", "multipart/form-data"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			if got := mediaType(tt.contentType); got != tt.want {
+				t.Errorf("mediaType(%q) = %q; want %q", tt.contentType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMediaTypeMatches(t *testing.T) {
+	tests := []struct {
+		media string
+		want  string
+		match bool
+	}{
+		{"application/json", "application/json", true},
+		{"application/json", "APPLICATION/JSON", true},
+		{"application/problem+json", `application/(problem\+)?json`, true},
+		{"application/json", "json", false},
+		{"application/jsonp", "application/json", false},
+		{"text/html", "text/.*", true},
+		{"text/html", "text/[", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.media+" "+tt.want, func(t *testing.T) {
+			if got := mediaTypeMatches(tt.media, tt.want); got != tt.match {
+				t.Errorf("mediaTypeMatches(%q, %q) = %v; want %v", tt.media, tt.want, got, tt.match)
+			}
+		})
+	}
+}
+
+func TestAssertContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		expect      string
+		want        string
+	}{
+		{"matches", "application/json; charset=utf-8", "application/json", ""},
+		{"different", "text/html", "application/json", "expected Content-Type application/json; got: text/html"},
+		{"regex", "application/problem+json", `application/(problem\+)?json`, ""},
+		{"suffix is not enough", "application/jsonp", "application/json", "expected Content-Type application/json; got: application/jsonp"},
+		{"missing", "", "application/json", "response has no Content-Type header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+			}))
+			defer server.Close()
+
+			err := runAgainst(t, server, map[string]any{"expected_output": "200", "expect_content_type": tt.expect})
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestAssertContentTypeSniffed(t *testing.T) {
+	// With no Content-Type set, net/http sniffs one from the body; the check
+	// applies to what was sent.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<!DOCTYPE html><html></html>"))
+	}))
+	defer server.Close()
+
+	err := runAgainst(t, server, map[string]any{"expected_output": "200", "expect_content_type": "application/json"})
+	checkError(t, err, "expected Content-Type application/json; got: text/html; charset=utf-8")
+}
+
+func TestValidateContentType(t *testing.T) {
+	config := `{"url": "http://example.com/", "expected_output": "200", "expect_content_type": "text/["}`
+	checkError(t, Validate(config), "invalid expect_content_type provided: text/[")
+}
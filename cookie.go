@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var sameSiteNames = map[http.SameSite]string{
+	http.SameSiteStrictMode: "Strict",
+	http.SameSiteLaxMode:    "Lax",
+	http.SameSiteNoneMode:   "None",
+}
+
+// setCookies returns the cookies set by resp and by every redirect response
+// that led to it, in the order they were received.
+func setCookies(resp *http.Response) []*http.Cookie {
+	cookies := []*http.Cookie{}
+	for ; resp != nil; resp = resp.Request.Response {
+		cookies = append(resp.Cookies(), cookies...)
+	}
+
+	return cookies
+}
+
+// cookieSecurityMatcher expects the inspected cookies to carry the configured
+// Secure, HttpOnly and SameSite attributes.
+type cookieSecurityMatcher struct{}
+
+func (cookieSecurityMatcher) ValidateConfig(conf Schema) error {
+	if !slices.Contains([]string{"any", "Strict", "Lax", "None"}, conf.CookieSameSite) {
+		return fmt.Errorf("invalid cookie_samesite provided: %v", conf.CookieSameSite)
+	}
+
+	return nil
+}
+
+func (cookieSecurityMatcher) Match(ctx context.Context, ex *Exchange) error {
+	conf := ex.Config
+	names := splitList(conf.CookieNames)
+	cookies := setCookies(ex.Response)
+
+	if len(cookies) == 0 {
+		return fmt.Errorf("response set no cookies")
+	}
+
+	for _, name := range names {
+		if !slices.ContainsFunc(cookies, func(c *http.Cookie) bool { return c.Name == name }) {
+			return fmt.Errorf("response did not set cookie %q", name)
+		}
+	}
+
+	for _, cookie := range cookies {
+		if len(names) > 0 && !slices.Contains(names, cookie.Name) {
+			continue
+		}
+
+		missing := []string{}
+		if conf.CookieSecure && !cookie.Secure {
+			missing = append(missing, "Secure")
+		}
+		if conf.CookieHTTPOnly && !cookie.HttpOnly {
+			missing = append(missing, "HttpOnly")
+		}
+		if conf.CookieSameSite != "any" && sameSiteNames[cookie.SameSite] != conf.CookieSameSite {
+			missing = append(missing, "SameSite="+conf.CookieSameSite)
+		}
+
+		if len(missing) > 0 {
+			return fmt.Errorf("cookie %q is missing %s", cookie.Name, strings.Join(missing, ", "))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// corsPreflightMatcher sends an OPTIONS preflight for the configured origin,
+// method and headers and expects the response to allow all of them.
+type corsPreflightMatcher struct{}
+
+func (corsPreflightMatcher) ValidateConfig(conf Schema) error {
+	if conf.CORSOrigin == "" {
+		return fmt.Errorf("corsPreflight requires cors_origin to be provided")
+	}
+
+	if conf.CORSMethod == "" {
+		return fmt.Errorf("corsPreflight requires cors_method to be provided")
+	}
+
+	return nil
+}
+
+func (corsPreflightMatcher) Match(ctx context.Context, ex *Exchange) error {
+	conf := ex.Config
+
+	req, err := ex.probeRequest(ctx, http.MethodOptions, false)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Origin", conf.CORSOrigin)
+	req.Header.Set("Access-Control-Request-Method", conf.CORSMethod)
+	requested := splitList(conf.CORSHeaders)
+	if len(requested) > 0 {
+		req.Header.Set("Access-Control-Request-Headers", strings.Join(requested, ", "))
+	}
+
+	resp, err := ex.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("encounted error while making preflight request: %v", err.Error())
+	}
+	defer drainBody(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("expected 2xx preflight status code; got: %d", resp.StatusCode)
+	}
+
+	allowOrigin := resp.Header.Get("Access-Control-Allow-Origin")
+	if allowOrigin != conf.CORSOrigin && !(allowOrigin == "*" && !conf.CORSCreds) {
+		return fmt.Errorf("Access-Control-Allow-Origin %q does not allow origin %q", allowOrigin, conf.CORSOrigin)
+	}
+
+	if conf.CORSCreds && resp.Header.Get("Access-Control-Allow-Credentials") != "true" {
+		return fmt.Errorf("expected Access-Control-Allow-Credentials: true; got: %q", resp.Header.Get("Access-Control-Allow-Credentials"))
+	}
+
+	methods := splitList(strings.Join(resp.Header.Values("Access-Control-Allow-Methods"), ","))
+	if !containsFold(methods, conf.CORSMethod) && !(containsFold(methods, "*") && !conf.CORSCreds) {
+		return fmt.Errorf("Access-Control-Allow-Methods %q does not allow %v", strings.Join(methods, ", "), conf.CORSMethod)
+	}
+
+	headers := splitList(strings.Join(resp.Header.Values("Access-Control-Allow-Headers"), ","))
+	for _, header := range requested {
+		if !containsFold(headers, header) && !(containsFold(headers, "*") && !conf.CORSCreds) {
+			return fmt.Errorf("Access-Control-Allow-Headers %q does not allow %v", strings.Join(headers, ", "), header)
+		}
+	}
+
+	return nil
+}
+
+// assertAllowOrigin expects the response to a request sent with origin to
+// allow that origin, as a browser would for a simple cross-origin request.
+// cors_credentials additionally requires credentials to be allowed, which
+// rules out the * wildcard.
+func assertAllowOrigin(ctx context.Context, ex *Exchange) error {
+	conf := ex.Config
+	if conf.Origin == "" {
+		return nil
+	}
+
+	allowOrigin := ex.Response.Header.Get("Access-Control-Allow-Origin")
+	if allowOrigin != conf.Origin && !(allowOrigin == "*" && !conf.CORSCreds) {
+		return fmt.Errorf("Access-Control-Allow-Origin %q does not allow origin %q", allowOrigin, conf.Origin)
+	}
+
+	if conf.CORSCreds && ex.Response.Header.Get("Access-Control-Allow-Credentials") != "true" {
+		return fmt.Errorf("expected Access-Control-Allow-Credentials: true; got: %q", ex.Response.Header.Get("Access-Control-Allow-Credentials"))
+	}
+
+	return nil
+}
+
+// splitList splits a comma-separated list, trimming space and dropping empty
+// entries.
+func splitList(raw string) []string {
+	items := []string{}
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func containsFold(items []string, want string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, want) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,137 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// preflightServer answers OPTIONS on /api with the given allow headers and
+// fails any preflight that lacks the check's bearer token.
+func preflightServer(t *testing.T, allow http.Header) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodOptions {
+			return
+		}
+		if r.URL.Path != "/api" || r.Header.Get("Authorization") != "Bearer t0k3n" || r.Header.Get("X-Team") != "blue" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.ContentLength > 0 {
+			t.Errorf("preflight sent a %d byte body", r.ContentLength)
+		}
+		for name, values := range allow {
+			w.Header()[name] = values
+		}
+	}))
+}
+
+func TestCORSPreflight(t *testing.T) {
+	tests := []struct {
+		name  string
+		allow http.Header
+		conf  map[string]any
+		want  string
+	}{
+		{"allowed", http.Header{
+			"Access-Control-Allow-Origin":  {"https://app.example"},
+			"Access-Control-Allow-Methods": {"GET, PUT"},
+			"Access-Control-Allow-Headers": {"X-Requested-With, content-type"},
+		}, map[string]any{"cors_method": "PUT", "cors_headers": "Content-Type"}, ""},
+		{"wildcards", http.Header{
+			"Access-Control-Allow-Origin":  {"*"},
+			"Access-Control-Allow-Methods": {"*"},
+			"Access-Control-Allow-Headers": {"*"},
+		}, map[string]any{"cors_method": "DELETE", "cors_headers": "X-Custom"}, ""},
+		{"wildcard with credentials", http.Header{
+			"Access-Control-Allow-Origin":      {"*"},
+			"Access-Control-Allow-Credentials": {"true"},
+			"Access-Control-Allow-Methods":     {"GET"},
+		}, map[string]any{"cors_credentials": true}, `does not allow origin "https://app.example"`},
+		{"credentials missing", http.Header{
+			"Access-Control-Allow-Origin":  {"https://app.example"},
+			"Access-Control-Allow-Methods": {"GET"},
+		}, map[string]any{"cors_credentials": true}, "expected Access-Control-Allow-Credentials: true"},
+		{"method", http.Header{
+			"Access-Control-Allow-Origin":  {"https://app.example"},
+			"Access-Control-Allow-Methods": {"GET, POST"},
+		}, map[string]any{"cors_method": "PATCH"}, "does not allow PATCH"},
+		{"header", http.Header{
+			"Access-Control-Allow-Origin":  {"https://app.example"},
+			"Access-Control-Allow-Methods": {"GET"},
+		}, map[string]any{"cors_headers": "Authorization"}, "does not allow Authorization"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := preflightServer(t, tt.allow)
+			defer server.Close()
+
+			conf := map[string]any{
+				"url":             server.URL + "/api",
+				"match_type":      "corsPreflight",
+				"cors_origin":     "https://app.example",
+				"auth":            "bearer",
+				"auth_token":      "t0k3n",
+				"request_headers": "X-Team: blue",
+				"verb":            "POST",
+				"content_type":    "application/json",
+				"body":            `{"a": 1}`,
+			}
+			for key, value := range tt.conf {
+				conf[key] = value
+			}
+
+			checkError(t, runAgainst(t, server, conf), tt.want)
+		})
+	}
+}
+
+func TestCORSPreflightTargetURL(t *testing.T) {
+	server := preflightServer(t, http.Header{
+		"Access-Control-Allow-Origin":  {"https://app.example"},
+		"Access-Control-Allow-Methods": {"GET"},
+	})
+	defer server.Close()
+
+	// The preflight goes to the url resolved for the team, not the raw one.
+	ctx := WithTarget(context.Background(), map[string]string{"base_url": server.URL})
+	config := `{"url": "/api", "match_type": "corsPreflight", "cors_origin": "https://app.example",
+		"auth": "bearer", "auth_token": "t0k3n", "request_headers": "X-Team: blue"}`
+
+	err := New(WithClient(server.Client())).Run(ctx, config)
+	if err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+}
+
+func TestAllowOrigin(t *testing.T) {
+	tests := []struct {
+		allowOrigin string
+		credentials bool
+		want        string
+	}{
+		{"https://app.example", false, ""},
+		{"*", false, ""},
+		{"*", true, `Access-Control-Allow-Origin "*" does not allow origin`},
+		{"https://other.example", false, "does not allow origin"},
+		{"", false, "does not allow origin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.allowOrigin, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Origin") != "https://app.example" {
+					w.WriteHeader(http.StatusBadRequest)
+				}
+				w.Header().Set("Access-Control-Allow-Origin", tt.allowOrigin)
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}))
+			defer server.Close()
+
+			err := runAgainst(t, server, map[string]any{"expected_output": "200", "origin": "https://app.example", "cors_credentials": tt.credentials})
+			checkError(t, err, tt.want)
+		})
+	}
+}
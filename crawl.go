@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// crawlMatcher fetches up to crawl_limit same-origin links found on the page
+// and fails when more than crawl_max_errors of them fail.
+type crawlMatcher struct{}
+
+func (crawlMatcher) ValidateConfig(conf Schema) error {
+	if conf.CrawlLimit < 1 {
+		return fmt.Errorf("crawl_limit must be at least 1; got: %d", conf.CrawlLimit)
+	}
+
+	if conf.CrawlMaxErrors < 0 {
+		return fmt.Errorf("crawl_max_errors must not be negative; got: %d", conf.CrawlMaxErrors)
+	}
+
+	return nil
+}
+
+func (crawlMatcher) Match(ctx context.Context, ex *Exchange) error {
+	if ex.Response.StatusCode < 200 || ex.Response.StatusCode > 299 {
+		return fmt.Errorf("expected 2xx status code for crawl start page; got: %d", ex.Response.StatusCode)
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	links := sameOriginLinks(ex.Response.Request.URL, scanHTML(string(body)), "a", "href")
+	if len(links) > ex.Config.CrawlLimit {
+		links = links[:ex.Config.CrawlLimit]
+	}
+
+	failures := []string{}
+	for _, link := range links {
+		resp, err := ex.fetch(ctx, link)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", link, err))
+			continue
+		}
+		drainBody(resp.Body)
+
+		if resp.StatusCode >= 400 {
+			failures = append(failures, fmt.Sprintf("%s: status %d", link, resp.StatusCode))
+		}
+	}
+
+	if len(failures) > ex.Config.CrawlMaxErrors {
+		return fmt.Errorf("%d of %d crawled links failed: %s", len(failures), len(links), strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+// sameOriginLinks resolves the attr of every tag element against base and
+// returns the distinct http(s) URLs sharing base's origin, without fragments,
+// in document order.
+func sameOriginLinks(base *url.URL, elements []htmlElement, tag string, attr string) []string {
+	seen := map[string]bool{base.String(): true}
+	links := []string{}
+
+	for _, element := range elements {
+		if element.Tag != tag {
+			continue
+		}
+
+		raw, ok := element.Attr(attr)
+		if !ok || strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		ref, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			continue
+		}
+
+		target := base.ResolveReference(ref)
+		target.Fragment = ""
+
+		if target.Scheme != base.Scheme || target.Host != base.Host {
+			continue
+		}
+
+		if seen[target.String()] {
+			continue
+		}
+		seen[target.String()] = true
+		links = append(links, target.String())
+	}
+
+	return links
+}
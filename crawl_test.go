@@ -0,0 +1,99 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestSameOriginLinks(t *testing.T) {
+	base, _ := url.Parse("https://example.com/docs/index.html")
+	page := `<a href="intro.html">
+<a href="/about#team">
+<a href="/about">
+<a href=" /contact ">
+<a href="">
+<a name="top">
+<a href="http://example.com/insecure">
+<a href="https://example.com:8443/other-port">
+<a href="https://cdn.example.com/lib.js">
+<a href="mailto:admin@example.com">
+<a href="#top">
+<link href="/style.css">`
+
+	got := sameOriginLinks(base, scanHTML(page), "a", "href")
+	want := []string{
+		"https://example.com/docs/intro.html",
+		"https://example.com/about",
+		"https://example.com/contact",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("sameOriginLinks() = %q; want %q", got, want)
+	}
+}
+
+func TestCrawl(t *testing.T) {
+	// The start page links to three pages, one of them broken, and to an
+	// external site that must never be fetched.
+	var mu sync.Mutex
+	fetched := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetched = append(fetched, r.URL.Path)
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<a href="/a">A</a> <a href="/broken">B</a> <a href="/c">C</a> <a href="http://external.invalid/">X</a>`))
+		case "/broken":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		conf    map[string]any
+		fetched []string
+		want    string
+	}{
+		{"broken link", nil, []string{"/", "/a", "/broken", "/c"}, "1 of 3 crawled links failed: " + server.URL + "/broken: status 404"},
+		{"tolerated", map[string]any{"crawl_max_errors": 1}, []string{"/", "/a", "/broken", "/c"}, ""},
+		{"limited", map[string]any{"crawl_limit": 1}, []string{"/", "/a"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			fetched = nil
+			mu.Unlock()
+
+			conf := map[string]any{"match_type": "crawl"}
+			for key, value := range tt.conf {
+				conf[key] = value
+			}
+
+			err := runAgainst(t, server, conf)
+			checkError(t, err, tt.want)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !slices.Equal(fetched, tt.fetched) {
+				t.Errorf("fetched %q; want %q", fetched, tt.fetched)
+			}
+		})
+	}
+}
+
+func TestCrawlStartPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := runAgainst(t, server, map[string]any{"match_type": "crawl"})
+	checkError(t, err, "expected 2xx status code for crawl start page; got: 500")
+}
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// crudMatcher treats the configured request as the create step of a REST
+// round trip. The created resource, found from the Location header or the
+// resource_id_path of the JSON response, must then read back with the posted
+// content, delete, and be gone afterwards.
+type crudMatcher struct{}
+
+func (crudMatcher) ValidateConfig(conf Schema) error {
+	if conf.Verb != http.MethodPost && conf.Verb != http.MethodPut {
+		return fmt.Errorf("crudRoundTrip requires verb POST or PUT; got: %v", conf.Verb)
+	}
+
+	_, err := parsePath(conf.ResourceIDPath)
+	if err != nil {
+		return fmt.Errorf("invalid resource_id_path provided: %v", err)
+	}
+
+	return nil
+}
+
+func (crudMatcher) Match(ctx context.Context, ex *Exchange) error {
+	conf := ex.Config
+
+	if ex.Response.StatusCode < 200 || ex.Response.StatusCode > 299 {
+		return fmt.Errorf("create: expected 2xx status code; got: %d", ex.Response.StatusCode)
+	}
+
+	resource, err := createdResource(ex)
+	if err != nil {
+		return fmt.Errorf("create: %v", err)
+	}
+
+	// Whatever fails from here on, the created resource is deleted so failed
+	// checks do not pile resources up on the team's service.
+	deleted := false
+	defer func() {
+		if !deleted {
+			crudStep(ctx, ex, http.MethodDelete, resource)
+		}
+	}()
+
+	body, status, err := crudStep(ctx, ex, http.MethodGet, resource)
+	if err != nil {
+		return fmt.Errorf("read %s: %v", resource, err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("read %s: expected status code: %d; got: %d", resource, http.StatusOK, status)
+	}
+
+	if conf.ContentType == "application/json" {
+		posted, err := decodeJSON(ex.RequestBody)
+		if err != nil {
+			return fmt.Errorf("body is not valid json: %v", err)
+		}
+
+		stored, err := decodeJSON(body)
+		if err != nil {
+			return fmt.Errorf("read %s: response body is not valid json: %v", resource, err)
+		}
+
+		if !jsonSubsetOf(posted, stored) {
+			return fmt.Errorf("read %s: resource does not contain the created content", resource)
+		}
+	}
+
+	if conf.ExpectedOutput != "" && !strings.Contains(string(body), conf.ExpectedOutput) {
+		return fmt.Errorf("read %s: expected output not found in response body", resource)
+	}
+
+	deleted = true
+	_, status, err = crudStep(ctx, ex, http.MethodDelete, resource)
+	if err != nil {
+		return fmt.Errorf("delete %s: %v", resource, err)
+	}
+	if status < 200 || status > 299 {
+		return fmt.Errorf("delete %s: expected 2xx status code; got: %d", resource, status)
+	}
+
+	_, status, err = crudStep(ctx, ex, http.MethodGet, resource)
+	if err != nil {
+		return fmt.Errorf("read after delete %s: %v", resource, err)
+	}
+	if status != http.StatusNotFound && status != http.StatusGone {
+		return fmt.Errorf("read after delete %s: expected status code 404 or 410; got: %d", resource, status)
+	}
+
+	return nil
+}
+
+// createdResource returns the URL of the resource the create step made.
+func createdResource(ex *Exchange) (string, error) {
+	base := ex.Response.Request.URL
+
+	if location := ex.Response.Header.Get("Location"); location != "" {
+		ref, err := url.Parse(location)
+		if err != nil {
+			return "", fmt.Errorf("invalid Location header: %v", location)
+		}
+		return base.ResolveReference(ref).String(), nil
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return "", err
+	}
+
+	doc, err := decodeJSON(body)
+	if err != nil {
+		return "", fmt.Errorf("no Location header and response body is not valid json: %v", err)
+	}
+
+	id, err := lookupPath(doc, ex.Config.ResourceIDPath)
+	if err != nil {
+		return "", fmt.Errorf("no Location header and %v", err)
+	}
+
+	return base.JoinPath(url.PathEscape(scalarString(id))).String(), nil
+}
+
+func crudStep(ctx context.Context, ex *Exchange, method string, target string) ([]byte, int, error) {
+	req, err := ex.followUp(ctx, method, target)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := ex.Client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("encounted error while making request: %v", err.Error())
+	}
+	defer drainBody(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("encountered error while reading response body: %v", err)
+	}
+
+	return body, resp.StatusCode, nil
+}
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// crudServer is an in-memory REST collection at /items. readStatus, when
+// set, replaces the status of reads, and mangle rewrites stored content.
+type crudServer struct {
+	mu         sync.Mutex
+	items      map[string][]byte
+	next       int
+	deletes    int
+	readStatus int
+	mangle     bool
+}
+
+func (s *crudServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := strings.TrimPrefix(r.URL.Path, "/items/")
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/items":
+		body, _ := io.ReadAll(r.Body)
+		if s.mangle {
+			body = []byte(`{"name": "something else"}`)
+		}
+		s.next++
+		id = strings.Repeat("x", s.next)
+		s.items[id] = body
+		w.Header().Set("Location", "/items/"+id)
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodGet:
+		body, ok := s.items[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if s.readStatus != 0 {
+			w.WriteHeader(s.readStatus)
+			return
+		}
+		w.Write(body)
+	case r.Method == http.MethodDelete:
+		s.deletes++
+		delete(s.items, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestCrudRoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		readStatus int
+		mangle     bool
+		want       string
+	}{
+		{"round trip", 0, false, ""},
+		{"read fails", http.StatusInternalServerError, false, "expected status code: 200; got: 500"},
+		{"content lost", 0, true, "does not contain the created content"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &crudServer{items: map[string][]byte{}, readStatus: tt.readStatus, mangle: tt.mangle}
+			server := httptest.NewServer(store)
+			defer server.Close()
+
+			config, _ := json.Marshal(map[string]any{
+				"url":          server.URL + "/items",
+				"verb":         "POST",
+				"match_type":   "crudRoundTrip",
+				"content_type": "application/json",
+				"body":         `{"name": "item-{{nonce}}"}`,
+			})
+
+			err := New().Run(context.Background(), string(config))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Run() = %v; want nil", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Run() = %v; want error containing %q", err, tt.want)
+			}
+
+			if len(store.items) != 0 || store.deletes != 1 {
+				t.Errorf("%d items left after %d deletes; want none left after 1", len(store.items), store.deletes)
+			}
+		})
+	}
+}
+
+func TestCreatedResource(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		location string
+		body     string
+		want     string
+	}{
+		{"location", "http://example.com/items", "/items/7", "", "http://example.com/items/7"},
+		{"relative location", "http://example.com/api/items/", "7", "", "http://example.com/api/items/7"},
+		{"id", "http://example.com/items", "", `{"id": 7}`, "http://example.com/items/7"},
+		{"trailing slash", "http://example.com/items/", "", `{"id": 7}`, "http://example.com/items/7"},
+		{"query", "http://example.com/items?key=abc", "", `{"id": 7}`, "http://example.com/items/7?key=abc"},
+		{"fragment", "http://example.com/items#top", "", `{"id": 7}`, "http://example.com/items/7#top"},
+		{"escaped id", "http://example.com/items", "", `{"id": "a/b c"}`, "http://example.com/items/a%2Fb%20c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, tt.url, nil)
+			resp := &http.Response{
+				Request: req,
+				Header:  http.Header{},
+				Body:    io.NopCloser(strings.NewReader(tt.body)),
+			}
+			if tt.location != "" {
+				resp.Header.Set("Location", tt.location)
+			}
+			ex := &Exchange{Config: Schema{ResourceIDPath: "id"}, Response: resp}
+
+			got, err := createdResource(ex)
+			if err != nil {
+				t.Fatalf("createdResource() = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("createdResource() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// csvValueMatcher parses the body as CSV and expects expected_output either
+// at csv_row in csv_column, or anywhere in that column when csv_row is -1.
+type csvValueMatcher struct{}
+
+func (csvValueMatcher) ValidateConfig(conf Schema) error {
+	if conf.CSVColumn == "" {
+		return fmt.Errorf("csvValue requires csv_column to be provided")
+	}
+
+	if !conf.CSVHeader {
+		index, err := strconv.Atoi(conf.CSVColumn)
+		if err != nil || index < 0 {
+			return fmt.Errorf("csv_column must be a zero-based index when csv_header is false; got: %v", conf.CSVColumn)
+		}
+	}
+
+	if conf.CSVRow < -1 {
+		return fmt.Errorf("csv_row must be -1 or a zero-based row; got: %d", conf.CSVRow)
+	}
+
+	return nil
+}
+
+func (csvValueMatcher) Match(ctx context.Context, ex *Exchange) error {
+	conf := ex.Config
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("response body is not valid csv: %v", err)
+	}
+
+	column := -1
+	if conf.CSVHeader {
+		if len(records) == 0 {
+			return fmt.Errorf("csv response has no header row")
+		}
+		column = slices.Index(records[0], conf.CSVColumn)
+		records = records[1:]
+	}
+	if column < 0 {
+		column, err = strconv.Atoi(conf.CSVColumn)
+		if err != nil || column < 0 {
+			return fmt.Errorf("csv response has no column %q", conf.CSVColumn)
+		}
+	}
+
+	cell := func(row []string) (string, bool) {
+		if column >= len(row) {
+			return "", false
+		}
+		return row[column], true
+	}
+
+	if conf.CSVRow >= 0 {
+		if conf.CSVRow >= len(records) {
+			return fmt.Errorf("csv response has %d data rows; wanted row %d", len(records), conf.CSVRow)
+		}
+
+		value, ok := cell(records[conf.CSVRow])
+		if !ok {
+			return fmt.Errorf("csv row %d has no column %q", conf.CSVRow, conf.CSVColumn)
+		}
+		if value != conf.ExpectedOutput {
+			return fmt.Errorf("expected csv row %d column %q to be %q; got: %q", conf.CSVRow, conf.CSVColumn, conf.ExpectedOutput, value)
+		}
+		return nil
+	}
+
+	for _, row := range records {
+		if value, ok := cell(row); ok && value == conf.ExpectedOutput {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("csv column %q does not contain %q", conf.CSVColumn, conf.ExpectedOutput)
+}
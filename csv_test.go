@@ -0,0 +1,96 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCSVValueMatcher(t *testing.T) {
+	body := "name,status\nweb,up\ndb,down\nshort\n"
+
+	tests := []struct {
+		name string
+		conf map[string]any
+		want string
+	}{
+		{"any row", map[string]any{"csv_column": "status", "expected_output": "down"}, ""},
+		{"fixed row", map[string]any{"csv_column": "status", "csv_row": 0, "expected_output": "up"}, ""},
+		{"fixed row differs", map[string]any{"csv_column": "status", "csv_row": 1, "expected_output": "up"}, `expected csv row 1 column "status" to be "up"; got: "down"`},
+		{"row out of range", map[string]any{"csv_column": "status", "csv_row": 5, "expected_output": "up"}, "csv response has 3 data rows; wanted row 5"},
+		{"short row", map[string]any{"csv_column": "status", "csv_row": 2, "expected_output": "up"}, `csv row 2 has no column "status"`},
+		{"column index", map[string]any{"csv_column": "0", "expected_output": "db"}, ""},
+		{"no header", map[string]any{"csv_column": "1", "csv_header": false, "csv_row": 0, "expected_output": "status"}, ""},
+		{"missing value", map[string]any{"csv_column": "status", "expected_output": "degraded"}, `csv column "status" does not contain "degraded"`},
+		{"missing column", map[string]any{"csv_column": "uptime", "expected_output": "up"}, `csv response has no column "uptime"`},
+		{"header row not searched", map[string]any{"csv_column": "status", "expected_output": "status"}, `csv column "status" does not contain "status"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			conf := map[string]any{"match_type": "csvValue"}
+			for key, value := range tt.conf {
+				conf[key] = value
+			}
+
+			err := runAgainst(t, server, conf)
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestCSVValueParsing(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		conf map[string]any
+		want string
+	}{
+		{"quoted comma", "name,motd\nweb,\"hello, world\"\n", map[string]any{"csv_column": "motd", "expected_output": "hello, world"}, ""},
+		{"quoted newline", "name,motd\nweb,\"line one\nline two\"\n", map[string]any{"csv_column": "motd", "csv_row": 0, "expected_output": "line one\nline two"}, ""},
+		{"header named like an index", "1,0\na,b\n", map[string]any{"csv_column": "0", "csv_row": 0, "expected_output": "b"}, ""},
+		{"empty body", "", map[string]any{"csv_column": "status", "expected_output": "up"}, "csv response has no header row"},
+		{"bare quote", "name,status\nweb,u\"p\n", map[string]any{"csv_column": "status", "expected_output": "up"}, "response body is not valid csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			conf := map[string]any{"match_type": "csvValue"}
+			for key, value := range tt.conf {
+				conf[key] = value
+			}
+
+			err := runAgainst(t, server, conf)
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestCSVValueValidateConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		conf Schema
+		want string
+	}{
+		{"named column", Schema{CSVColumn: "status", CSVHeader: true, CSVRow: -1}, ""},
+		{"no column", Schema{CSVHeader: true, CSVRow: -1}, "requires csv_column"},
+		{"name without header", Schema{CSVColumn: "status", CSVRow: -1}, "must be a zero-based index"},
+		{"bad row", Schema{CSVColumn: "status", CSVHeader: true, CSVRow: -2}, "csv_row must be -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkError(t, csvValueMatcher{}.ValidateConfig(tt.conf), tt.want)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/scorify/schema"
+)
+
+// decodeConfig parses a JSON config into a Schema. Keys the config leaves out
+// take their default tag, or the zero value, so configs written before a
+// field existed keep working. A value of the wrong type is an error rather
+// than a panic in schema.Unmarshal.
+func decodeConfig(config string) (Schema, error) {
+	conf := Schema{}
+
+	doc := map[string]any{}
+	err := json.Unmarshal([]byte(config), &doc)
+	if err != nil {
+		return conf, err
+	}
+
+	doc, err = withDefaults(doc)
+	if err != nil {
+		return conf, err
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return conf, err
+	}
+
+	err = schema.Unmarshal(data, &conf)
+	return conf, err
+}
+
+// withDefaults returns a copy of doc with every Schema key present, absent
+// keys filled from their default tag, and each value checked against its
+// field's kind.
+func withDefaults(doc map[string]any) (map[string]any, error) {
+	filled := make(map[string]any, len(doc))
+	for key, value := range doc {
+		filled[key] = value
+	}
+
+	t := reflect.TypeOf(Schema{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("key")
+
+		value, ok := filled[key]
+		if !ok {
+			def, err := defaultValue(field)
+			if err != nil {
+				return nil, err
+			}
+			filled[key] = def
+			continue
+		}
+
+		err := checkKind(key, field.Type.Kind(), value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return filled, nil
+}
+
+// defaultValue returns field's default tag as a JSON value of its kind.
+func defaultValue(field reflect.StructField) (any, error) {
+	def := field.Tag.Get("default")
+
+	switch field.Type.Kind() {
+	case reflect.String:
+		return def, nil
+	case reflect.Int:
+		if def == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(def)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default for %s: %v", field.Name, err)
+		}
+		return n, nil
+	case reflect.Bool:
+		if def == "" {
+			return false, nil
+		}
+		b, err := strconv.ParseBool(def)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default for %s: %v", field.Name, err)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unsupported field type for %s: %v", field.Name, field.Type)
+	}
+}
+
+// checkKind rejects a JSON value schema.Unmarshal cannot store in a field of
+// kind.
+func checkKind(key string, kind reflect.Kind, value any) error {
+	ok := false
+
+	switch kind {
+	case reflect.String:
+		_, ok = value.(string)
+	case reflect.Int:
+		n, isNumber := value.(float64)
+		ok = isNumber && n == math.Trunc(n)
+	case reflect.Bool:
+		_, ok = value.(bool)
+	}
+
+	if !ok {
+		return fmt.Errorf("invalid value for %s: expected %s; got: %v", key, kindName(kind), value)
+	}
+
+	return nil
+}
+
+func kindName(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int:
+		return "integer"
+	case reflect.Bool:
+		return "boolean"
+	default:
+		return kind.String()
+	}
+}
+
+// coerceConfig converts the scalar values of a hand-written config to their
+// field's kind, so expected_output: 200 becomes "200" and retries: "3"
+// becomes 3. Values that cannot be converted are an error.
+func coerceConfig(doc map[string]any) (map[string]any, error) {
+	coerced := make(map[string]any, len(doc))
+	for key, value := range doc {
+		coerced[key] = value
+	}
+
+	t := reflect.TypeOf(Schema{})
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("key")
+		kind := t.Field(i).Type.Kind()
+
+		value, ok := coerced[key]
+		if !ok {
+			continue
+		}
+
+		converted, ok := coerceValue(kind, value)
+		if !ok {
+			return nil, fmt.Errorf("invalid value for %s: expected %s; got: %v", key, kindName(kind), value)
+		}
+		coerced[key] = converted
+	}
+
+	return coerced, nil
+}
+
+func coerceValue(kind reflect.Kind, value any) (any, bool) {
+	switch kind {
+	case reflect.String:
+		switch v := value.(type) {
+		case string:
+			return v, true
+		case bool:
+			return strconv.FormatBool(v), true
+		case int:
+			return strconv.Itoa(v), true
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), true
+		}
+	case reflect.Int:
+		switch v := value.(type) {
+		case int:
+			return float64(v), true
+		case float64:
+			return v, v == math.Trunc(v)
+		case string:
+			n, err := strconv.Atoi(v)
+			return float64(n), err == nil
+		}
+	case reflect.Bool:
+		switch v := value.(type) {
+		case bool:
+			return v, true
+		case string:
+			b, err := strconv.ParseBool(v)
+			return b, err == nil
+		}
+	}
+
+	return nil, false
+}
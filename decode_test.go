@@ -0,0 +1,69 @@
+package http
+
+import (
+	"strings"
+	"testing"
+)
+
+// baselineConfig has only the eight keys the original schema defined.
+const baselineConfig = `{
+	"url": "http://example.com/",
+	"verb": "GET",
+	"expected_output": "200",
+	"match_type": "statusCode",
+	"insecure": false,
+	"headers": "",
+	"body": "",
+	"content_type": "empty"
+}`
+
+func TestValidateBaselineConfig(t *testing.T) {
+	err := Validate(baselineConfig)
+	if err != nil {
+		t.Fatalf("Validate(baseline) = %v; want nil", err)
+	}
+}
+
+func TestDecodeConfigDefaults(t *testing.T) {
+	conf, err := decodeConfig(`{"url": "http://example.com/", "expected_output": "200"}`)
+	if err != nil {
+		t.Fatalf("decodeConfig() = %v", err)
+	}
+
+	if conf.Verb != "GET" || conf.MatchType != "statusCode" || conf.ContentType != "empty" {
+		t.Errorf("string defaults not applied: verb=%q match_type=%q content_type=%q", conf.Verb, conf.MatchType, conf.ContentType)
+	}
+	if conf.MaxRedirects != 10 {
+		t.Errorf("max_redirects = %d; want default 10", conf.MaxRedirects)
+	}
+	if !conf.CSVHeader {
+		t.Errorf("csv_header = %v; want default true", conf.CSVHeader)
+	}
+	if conf.Insecure {
+		t.Errorf("insecure = true; want zero value")
+	}
+}
+
+func TestDecodeConfigTypeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{"bool for string", `{"url": true}`, "invalid value for url"},
+		{"string for int", `{"url": "http://x/", "retries": "many"}`, "invalid value for retries"},
+		{"fraction for int", `{"url": "http://x/", "retries": 1.5}`, "invalid value for retries"},
+		{"string for bool", `{"url": "http://x/", "insecure": "maybe"}`, "invalid value for insecure"},
+		{"null", `{"url": null}`, "invalid value for url"},
+		{"not json", `{`, "unexpected end of JSON input"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeConfig(tt.config)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("decodeConfig(%s) = %v; want error containing %q", tt.config, err, tt.want)
+			}
+		})
+	}
+}
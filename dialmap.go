@@ -0,0 +1,56 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// parseDialMap parses lines of "host:port=address:port".
+func parseDialMap(raw string) (map[string]string, error) {
+	table := map[string]string{}
+	for _, line := range splitLines(raw) {
+		from, to, ok := strings.Cut(line, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok {
+			return nil, fmt.Errorf("dial_map lines must be \"host:port=address:port\" ; got: %v", line)
+		}
+
+		for _, address := range []string{from, to} {
+			host, port, err := net.SplitHostPort(address)
+			if err != nil || host == "" || port == "" {
+				return nil, fmt.Errorf("dial_map lines must be \"host:port=address:port\" ; got: %v", line)
+			}
+		}
+
+		table[strings.ToLower(from)] = to
+	}
+
+	return table, nil
+}
+
+// mappedDialer dials the address a dial_map entry gives in place of the
+// host:port a URL or redirect names. TLS still verifies the original host.
+type mappedDialer struct {
+	Dialer
+	table map[string]string
+}
+
+func (d mappedDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if to, ok := d.table[strings.ToLower(address)]; ok {
+		address = to
+	}
+
+	return d.Dialer.DialContext(ctx, network, address)
+}
+
+// withDialMap wraps dialer with the dial_map in raw, if there is one.
+func withDialMap(dialer Dialer, raw string) Dialer {
+	table, err := parseDialMap(raw)
+	if err != nil || len(table) == 0 {
+		return dialer
+	}
+
+	return mappedDialer{Dialer: dialer, table: table}
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseDialMap(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want map[string]string
+		err  string
+	}{
+		{"empty", "", map[string]string{}, ""},
+		{"entries", "WWW.example.com:443=10.0.0.5:8443\r\n\n api.example.com:80 = 10.0.0.6:80", map[string]string{"www.example.com:443": "10.0.0.5:8443", "api.example.com:80": "10.0.0.6:80"}, ""},
+		{"ipv6", "example.com:443=[fd00::5]:443", map[string]string{"example.com:443": "[fd00::5]:443"}, ""},
+		{"no separator", "example.com:443", nil, "dial_map lines must be"},
+		{"no port", "example.com=10.0.0.5:443", nil, "dial_map lines must be"},
+		{"no target host", "example.com:443=:443", nil, "dial_map lines must be"},
+		{"later line wins", "example.com:443=10.0.0.5:443\nexample.com:443=10.0.0.6:443", map[string]string{"example.com:443": "10.0.0.6:443"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDialMap(tt.raw)
+			checkError(t, err, tt.err)
+			if tt.err == "" && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDialMap(%q) = %v; want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+// recordingDialer records the address it was asked to dial and fails.
+type recordingDialer struct {
+	address *string
+}
+
+func (d recordingDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	*d.address = address
+	return nil, errors.New("not dialing")
+}
+
+func TestWithDialMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		address string
+		want    string
+	}{
+		{"mapped", "example.com:443=10.0.0.5:8443", "EXAMPLE.com:443", "10.0.0.5:8443"},
+		{"other port", "example.com:443=10.0.0.5:8443", "example.com:80", "example.com:80"},
+		{"invalid map", "example.com", "example.com:443", "example.com:443"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialed := ""
+			dialer := withDialMap(recordingDialer{address: &dialed}, tt.raw)
+
+			dialer.DialContext(context.Background(), "tcp", tt.address)
+			if dialed != tt.want {
+				t.Errorf("dialed %q; want %q", dialed, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialMapRun(t *testing.T) {
+	// api.internal answers only when asked for by name, as a virtual host.
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != "api.internal" {
+			w.WriteHeader(http.StatusMisdirectedRequest)
+			return
+		}
+		w.Write([]byte("api ok"))
+	}))
+	defer api.Close()
+
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://api.internal/status", http.StatusFound)
+	}))
+	defer app.Close()
+
+	table := "app.internal:80=" + app.Listener.Addr().String() + "\napi.internal:80=" + api.Listener.Addr().String()
+
+	tests := []struct {
+		name string
+		url  string
+		dial string
+		want string
+	}{
+		{"mapped", "http://api.internal/", table, ""},
+		{"redirect mapped", "http://app.internal/", table, ""},
+		{"redirect unmapped", "http://app.internal/", "app.internal:80=" + app.Listener.Addr().String(), "encounted error while making request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, _ := json.Marshal(map[string]any{
+				"url":             tt.url,
+				"dial_map":        tt.dial,
+				"match_type":      "substringMatch",
+				"expected_output": "api ok",
+			})
+
+			err := New().Run(context.Background(), string(config))
+			checkError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RenderedRequest is the request a config would send, as produced by DryRun.
+type RenderedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+// DryRun validates config and returns the fully-rendered request it describes
+// without sending anything, with credentials redacted. When url lists several
+// URLs, the request to the first is rendered.
+func DryRun(config string) (*RenderedRequest, error) {
+	err := Validate(config)
+	if err != nil {
+		return nil, err
+	}
+
+	conf, err := decodeConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if conf.MatchType == "webhook" {
+		ctx = WithTarget(ctx, map[string]string{"callback_url": "http://callback.invalid/"})
+	}
+
+	conf.URL, err = resolveURL(ctx, conf.URL, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	conf.URL = targets(conf)[0]
+	conf = presetRequest(conf)
+
+	req, _, err := prepareRequest(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered := &RenderedRequest{
+		Method: req.Method,
+		URL:    redact(conf, req.URL.Redacted()),
+		Header: redactHeader(conf, req.Header),
+	}
+
+	body, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		rendered.Body = redact(conf, string(body))
+	}
+
+	return rendered, nil
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+)
+
+var envPattern = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// envFields are the keys of the credential fields expandEnv fills in. Other
+// fields are sent or matched as written, so a body or expected_output may
+// contain ${...} and the engine's environment cannot leak into a url.
+var envFields = []string{"auth_username", "auth_password", "auth_token", "jwt_key", "jwt_verify_key", "sign_secret"}
+
+// expandEnv replaces ${NAME} in the credential fields of conf with the value
+// of environment variable NAME, so secrets such as auth_password: ${TEAM_PASS}
+// need not be stored in the config. $${NAME} is left as a literal ${NAME}.
+// It runs before a config is validated or checked.
+func expandEnv(conf Schema) (Schema, error) {
+	value := reflect.ValueOf(&conf).Elem()
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		key := value.Type().Field(i).Tag.Get("key")
+		if !slices.Contains(envFields, key) {
+			continue
+		}
+
+		var missing string
+		expanded := envPattern.ReplaceAllStringFunc(field.String(), func(match string) string {
+			if match[1] == '$' {
+				return match[1:]
+			}
+
+			name := envPattern.FindStringSubmatch(match)[1]
+			env, ok := os.LookupEnv(name)
+			if !ok && missing == "" {
+				missing = name
+			}
+			return env
+		})
+
+		if missing != "" {
+			return conf, fmt.Errorf("environment variable %s referenced by %s is not set", missing, key)
+		}
+
+		field.SetString(expanded)
+	}
+
+	return conf, nil
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("SCORIFY_TEST_PASS", "hunter2")
+	t.Setenv("SCORIFY_TEST_HOST", "10.0.0.5")
+
+	tests := []struct {
+		name string
+		conf Schema
+		want Schema
+		err  string
+	}{
+		{"password", Schema{AuthPassword: "${SCORIFY_TEST_PASS}"}, Schema{AuthPassword: "hunter2"}, ""},
+		{"several", Schema{SignSecret: "${SCORIFY_TEST_HOST}:${SCORIFY_TEST_PASS}"}, Schema{SignSecret: "10.0.0.5:hunter2"}, ""},
+		{"escaped", Schema{AuthToken: "$${SCORIFY_TEST_PASS}"}, Schema{AuthToken: "${SCORIFY_TEST_PASS}"}, ""},
+		{"bare dollar", Schema{AuthPassword: "$SCORIFY_TEST_PASS costs $5"}, Schema{AuthPassword: "$SCORIFY_TEST_PASS costs $5"}, ""},
+		{"missing", Schema{AuthToken: "${SCORIFY_TEST_UNSET}"}, Schema{}, "environment variable SCORIFY_TEST_UNSET referenced by auth_token is not set"},
+		{"url untouched", Schema{URL: "http://${SCORIFY_TEST_HOST}/"}, Schema{URL: "http://${SCORIFY_TEST_HOST}/"}, ""},
+		{"body untouched", Schema{Body: "${SCORIFY_TEST_UNSET}"}, Schema{Body: "${SCORIFY_TEST_UNSET}"}, ""},
+		{"ints untouched", Schema{Retries: 3}, Schema{Retries: 3}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandEnv(tt.conf)
+			checkError(t, err, tt.err)
+			if tt.err == "" && got != tt.want {
+				t.Errorf("expandEnv() = %+v; want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("SCORIFY_TEST_PASS", "hunter2")
+
+	// The server echoes the body, which must arrive as written.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, password, _ := r.BasicAuth()
+		if password != "hunter2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		password string
+		want     string
+	}{
+		{"expanded", "${SCORIFY_TEST_PASS}", ""},
+		{"missing", "${SCORIFY_TEST_UNSET}", "environment variable SCORIFY_TEST_UNSET referenced by auth_password is not set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runAgainst(t, server, map[string]any{
+				"verb":            "POST",
+				"auth":            "basic",
+				"auth_password":   tt.password,
+				"content_type":    "plain/text",
+				"body":            "${SCORIFY_TEST_PASS}",
+				"match_type":      "exactMatch",
+				"expected_output": "${SCORIFY_TEST_PASS}",
+			})
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestValidateExpandsEnv(t *testing.T) {
+	// A placeholder is not a PEM key; only its value is.
+	t.Setenv("SCORIFY_TEST_KEY", wrongJWTKey("RS256", ""))
+
+	config, _ := json.Marshal(map[string]any{
+		"url":             "http://example.com/",
+		"expected_output": "200",
+		"auth":            "jwt",
+		"jwt_algorithm":   "RS256",
+		"jwt_key":         "${SCORIFY_TEST_KEY}",
+	})
+
+	checkError(t, Validate(string(config)), "")
+}
@@ -0,0 +1,23 @@
+package http
+
+import (
+	"context"
+	"errors"
+)
+
+// sendWithFallback sends the configured request and, if no response is
+// received, sends it to fallback_url instead. A rejected TLS handshake is an
+// answer from the primary, not an outage, so it does not fall back.
+func (c *Checker) sendWithFallback(ctx context.Context, conf Schema, res *Result) (*Exchange, error) {
+	ex, err := c.send(ctx, conf, res)
+
+	var reqErr *requestError
+	if conf.FallbackURL == "" || !errors.As(err, &reqErr) || verificationFailed(err) || ctx.Err() != nil {
+		return ex, err
+	}
+
+	res.Note("%s unreachable, fell back to %s: %v", conf.URL, conf.FallbackURL, reqErr.err)
+	conf.URL = conf.FallbackURL
+
+	return c.send(ctx, conf, res)
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFallbackURL(t *testing.T) {
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("fallback"))
+	}))
+	defer fallback.Close()
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	down.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	untrusted := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("fallback"))
+	}))
+	untrusted.Config.ErrorLog = log.New(io.Discard, "", 0)
+	untrusted.StartTLS()
+	defer untrusted.Close()
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+		note bool
+	}{
+		{"unreachable primary", down.URL, "", true},
+		{"failing response", failing.URL, "expected output not found", false},
+		{"rejected certificate", untrusted.URL, "certificate", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, _ := json.Marshal(map[string]any{
+				"url":             tt.url,
+				"fallback_url":    fallback.URL,
+				"expected_output": "fallback",
+				"match_type":      "substringMatch",
+			})
+
+			res, err := New(WithClient(fallback.Client())).Check(context.Background(), string(config))
+			checkError(t, err, tt.want)
+
+			fellBack := false
+			for _, note := range res.Notes {
+				fellBack = fellBack || strings.Contains(note, "fell back to "+fallback.URL)
+			}
+			if fellBack != tt.note {
+				t.Errorf("Notes = %q; want fallback note %v", res.Notes, tt.note)
+			}
+		})
+	}
+}
@@ -2,4 +2,8 @@ module github.com/scorify/http
 
 go 1.24.0
 
-require github.com/scorify/schema v0.0.0
+require (
+	github.com/scorify/schema v0.0.0
+	golang.org/x/crypto v0.9.0
+	gopkg.in/yaml.v3 v3.0.1
+)
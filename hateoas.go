@@ -0,0 +1,105 @@
+package http
+
+import (
+	"context"
+	"fmt"
+)
+
+// followLinkMatcher reads a link from the JSON response at link_path, follows
+// it, and applies link_match_type, with expected_output, to the response it
+// leads to. The link may be a URL string or an object with an href, as in
+// HAL's {"_links": {"next": {"href": "/orders/2"}}}.
+type followLinkMatcher struct{}
+
+func (followLinkMatcher) ValidateConfig(conf Schema) error {
+	if conf.LinkPath == "" {
+		return fmt.Errorf("followLink requires link_path to be provided")
+	}
+
+	_, err := parsePath(conf.LinkPath)
+	if err != nil {
+		return err
+	}
+
+	if conf.LinkMatchType == "followLink" {
+		return fmt.Errorf("link_match_type cannot be followLink")
+	}
+
+	matcher, ok := lookupMatcher(conf.LinkMatchType)
+	if !ok {
+		return fmt.Errorf("invalid link_match_type provided: %v", conf.LinkMatchType)
+	}
+
+	if v, ok := matcher.(ConfigValidator); ok {
+		return v.ValidateConfig(conf)
+	}
+
+	if conf.ExpectedOutput == "" {
+		return fmt.Errorf("expected_output must be provided; got: %v", conf.ExpectedOutput)
+	}
+
+	return nil
+}
+
+func (followLinkMatcher) Match(ctx context.Context, ex *Exchange) error {
+	conf := ex.Config
+
+	if ex.Response.StatusCode < 200 || ex.Response.StatusCode > 299 {
+		return fmt.Errorf("expected 2xx status code before following link; got: %d", ex.Response.StatusCode)
+	}
+
+	matcher, ok := lookupMatcher(conf.LinkMatchType)
+	if !ok {
+		return fmt.Errorf("invalid link_match_type provided: %v", conf.LinkMatchType)
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	doc, err := decodeJSON(body)
+	if err != nil {
+		return fmt.Errorf("response body is not valid json: %v", err)
+	}
+
+	value, err := lookupPath(doc, conf.LinkPath)
+	if err != nil {
+		return err
+	}
+
+	if object, ok := value.(map[string]any); ok {
+		value = object["href"]
+	}
+
+	link, ok := value.(string)
+	if !ok || link == "" {
+		return fmt.Errorf("expected a link at %s; got: %s", conf.LinkPath, jsonType(value))
+	}
+
+	target, err := ex.Response.Request.URL.Parse(link)
+	if err != nil {
+		return fmt.Errorf("invalid link at %s: %v; %q", conf.LinkPath, link, err)
+	}
+
+	resp, err := ex.fetch(ctx, target.String())
+	if err != nil {
+		return err
+	}
+	defer drainBody(resp.Body)
+
+	followed := &Exchange{
+		Config:   conf,
+		Request:  resp.Request,
+		Response: resp,
+		Client:   ex.Client,
+		Result:   ex.Result,
+	}
+
+	err = matcher.Match(ctx, followed)
+	if err != nil {
+		return fmt.Errorf("followed link %s: %v", target, err)
+	}
+
+	return nil
+}
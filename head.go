@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// headConsistentMatcher repeats the GET as a HEAD and expects the status code
+// and Content-Length to agree, catching proxies that mishandle HEAD.
+type headConsistentMatcher struct{}
+
+func (headConsistentMatcher) ValidateConfig(conf Schema) error {
+	if conf.Verb != "GET" {
+		return fmt.Errorf("headConsistent requires the GET verb; got: %v", conf.Verb)
+	}
+
+	return nil
+}
+
+func (headConsistentMatcher) Match(ctx context.Context, ex *Exchange) error {
+	req, err := ex.NewRequest(ctx)
+	if err != nil {
+		return err
+	}
+	req.Method = http.MethodHead
+
+	resp, err := ex.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("encounted error while making HEAD request: %v", err.Error())
+	}
+	defer drainBody(resp.Body)
+
+	if resp.StatusCode != ex.Response.StatusCode {
+		return fmt.Errorf("HEAD status code %d does not match GET status code %d", resp.StatusCode, ex.Response.StatusCode)
+	}
+
+	// A transparently decompressed GET no longer knows its length on the
+	// wire, so only the status codes can be compared.
+	if ex.Response.Uncompressed {
+		return nil
+	}
+
+	length := ex.Response.ContentLength
+	if length < 0 {
+		body, err := readBody(ex)
+		if err != nil {
+			return err
+		}
+		length = int64(len(body))
+	}
+
+	if resp.ContentLength >= 0 && resp.ContentLength != length {
+		return fmt.Errorf("HEAD Content-Length %d does not match GET length %d", resp.ContentLength, length)
+	}
+
+	if resp.ContentLength < 0 && ex.Response.ContentLength >= 0 {
+		return fmt.Errorf("HEAD response has no Content-Length but GET has %d", ex.Response.ContentLength)
+	}
+
+	return nil
+}
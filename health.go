@@ -0,0 +1,86 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// healthyValues are the health_field values healthcheck accepts when
+// expected_output is empty.
+var healthyValues = []string{"ok", "healthy", "up", "pass"}
+
+// healthcheckRequest points a healthcheck config at health_path when url has
+// no path of its own, and makes it a plain GET.
+func healthcheckRequest(conf Schema) Schema {
+	conf.Verb = http.MethodGet
+
+	target, err := url.Parse(conf.URL)
+	if err != nil || (target.Path != "" && target.Path != "/") {
+		return conf
+	}
+
+	ref, err := url.Parse(conf.HealthPath)
+	if err != nil {
+		return conf
+	}
+	conf.URL = target.ResolveReference(ref).String()
+
+	return conf
+}
+
+// healthcheckMatcher expects a 200 JSON response whose health_field is
+// expected_output or, when that is empty, one of healthyValues.
+type healthcheckMatcher struct{}
+
+func (healthcheckMatcher) ValidateConfig(conf Schema) error {
+	_, err := url.Parse(conf.HealthPath)
+	if err != nil {
+		return fmt.Errorf("invalid health_path provided: %v; %q", conf.HealthPath, err)
+	}
+
+	_, err = parsePath(conf.HealthField)
+	if err != nil {
+		return fmt.Errorf("invalid health_field provided: %v", err)
+	}
+
+	return nil
+}
+
+func (healthcheckMatcher) Match(ctx context.Context, ex *Exchange) error {
+	if ex.Response.StatusCode != http.StatusOK {
+		return fmt.Errorf("expected status code: %d; got: %d", http.StatusOK, ex.Response.StatusCode)
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	doc, err := decodeJSON(body)
+	if err != nil {
+		return fmt.Errorf("response body is not valid json: %v", err)
+	}
+
+	value, err := lookupPath(doc, ex.Config.HealthField)
+	if err != nil {
+		return err
+	}
+
+	status := scalarString(value)
+	if ex.Config.ExpectedOutput != "" {
+		if status != ex.Config.ExpectedOutput {
+			return fmt.Errorf("expected %s to be %q; got: %q", ex.Config.HealthField, ex.Config.ExpectedOutput, status)
+		}
+		return nil
+	}
+
+	if !slices.Contains(healthyValues, strings.ToLower(status)) {
+		return fmt.Errorf("expected %s to be one of %s; got: %q", ex.Config.HealthField, strings.Join(healthyValues, ", "), status)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// interimResponse is a 1xx response received before the final one.
+type interimResponse struct {
+	code   int
+	header http.Header
+}
+
+func (t *tracer) interimResponses() []interimResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return slices.Clone(t.interim)
+}
+
+// observeInterim notes any 1xx responses that preceded the final one; the
+// transport consumes them, so they are otherwise invisible.
+func observeInterim(ex *Exchange) {
+	if ex.tracer == nil {
+		return
+	}
+
+	codes := []string{}
+	for _, interim := range ex.tracer.interimResponses() {
+		codes = append(codes, fmt.Sprint(interim.code))
+	}
+
+	if len(codes) > 0 {
+		ex.Result.Note("interim responses before %d: %s", ex.Response.StatusCode, strings.Join(codes, ", "))
+	}
+}
+
+// assertEarlyHints expects a 103 Early Hints response to have preceded the
+// final one, with Link headers mentioning each of early_hints_links.
+func assertEarlyHints(ctx context.Context, ex *Exchange) error {
+	if !ex.Config.ExpectEarlyHints {
+		return nil
+	}
+
+	links := []string{}
+	found := false
+	if ex.tracer != nil {
+		for _, interim := range ex.tracer.interimResponses() {
+			if interim.code == http.StatusEarlyHints {
+				found = true
+				links = append(links, interim.header.Values("Link")...)
+			}
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("expected a %d Early Hints response; got none", http.StatusEarlyHints)
+	}
+
+	joined := strings.Join(links, ", ")
+	for _, want := range splitLines(ex.Config.EarlyHintsLinks) {
+		want = strings.TrimSpace(want)
+		if !strings.Contains(joined, want) {
+			return fmt.Errorf("early hints Link headers do not mention %s; got: %s", want, joined)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// hintsServer sends a 103 Early Hints response with links, if any, before
+// its final 200.
+func hintsServer(links ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(links) > 0 {
+			for _, link := range links {
+				w.Header().Add("Link", link)
+			}
+			w.WriteHeader(http.StatusEarlyHints)
+			w.Header().Del("Link")
+		}
+		w.Write([]byte("ok"))
+	}))
+}
+
+func TestEarlyHints(t *testing.T) {
+	tests := []struct {
+		name  string
+		links []string
+		want  []string
+		err   string
+	}{
+		{"hinted", []string{"</style.css>; rel=preload; as=style"}, []string{"</style.css>"}, ""},
+		{"any hint will do", []string{"</app.js>; rel=preload; as=script"}, nil, ""},
+		{"several headers", []string{"</style.css>; rel=preload", "</app.js>; rel=preload"}, []string{"</style.css>", "</app.js>"}, ""},
+		{"missing link", []string{"</style.css>; rel=preload"}, []string{"</app.js>"}, "early hints Link headers do not mention </app.js>; got: </style.css>; rel=preload"},
+		{"no hints", nil, nil, "expected a 103 Early Hints response; got none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := hintsServer(tt.links...)
+			defer server.Close()
+
+			conf := map[string]any{
+				"expected_output":    "200",
+				"expect_early_hints": true,
+			}
+			if len(tt.want) > 0 {
+				conf["early_hints_links"] = strings.Join(tt.want, "\n")
+			}
+
+			err := runAgainst(t, server, conf)
+			checkError(t, err, tt.err)
+		})
+	}
+}
+
+func TestInterimResponsesNoted(t *testing.T) {
+	server := hintsServer("</style.css>; rel=preload")
+	defer server.Close()
+
+	config, _ := json.Marshal(map[string]any{
+		"url":             server.URL,
+		"expected_output": "200",
+	})
+
+	// Without expect_early_hints the 103 passes through and is only noted.
+	res, err := New(WithClient(server.Client())).Check(context.Background(), string(config))
+	if err != nil {
+		t.Fatalf("Check() = %v; want nil", err)
+	}
+	if res.Status != http.StatusOK {
+		t.Errorf("Status = %d; want 200", res.Status)
+	}
+	if !slices.Contains(res.Notes, "interim responses before 200: 103") {
+		t.Errorf("Notes = %q; want the interim response noted", res.Notes)
+	}
+}
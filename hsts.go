@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseDirectives splits a header value such as Strict-Transport-Security or
+// Cache-Control into lower-cased directive names and their unquoted values.
+// A repeated directive is an error.
+func parseDirectives(header string, separator string) (map[string]string, error) {
+	directives := map[string]string{}
+
+	for _, part := range strings.Split(header, separator) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, value, _ := strings.Cut(part, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = value[1 : len(value)-1]
+		}
+
+		if _, dup := directives[name]; dup {
+			return nil, fmt.Errorf("directive %q appears more than once", name)
+		}
+		directives[name] = value
+	}
+
+	return directives, nil
+}
+
+// hstsMatcher parses Strict-Transport-Security and expects max-age of at
+// least hsts_min_max_age plus any required includeSubDomains and preload.
+type hstsMatcher struct{}
+
+func (hstsMatcher) ValidateConfig(conf Schema) error {
+	if conf.HSTSMinMaxAge < 0 {
+		return fmt.Errorf("hsts_min_max_age must not be negative; got: %d", conf.HSTSMinMaxAge)
+	}
+
+	return nil
+}
+
+func (hstsMatcher) Match(ctx context.Context, ex *Exchange) error {
+	if ex.Response.TLS == nil {
+		return fmt.Errorf("hsts requires an https url; browsers ignore Strict-Transport-Security over http")
+	}
+
+	values := ex.Response.Header.Values("Strict-Transport-Security")
+	if len(values) == 0 {
+		return fmt.Errorf("response has no Strict-Transport-Security header")
+	}
+
+	// RFC 6797 section 8.1: only the first header field is processed.
+	directives, err := parseDirectives(values[0], ";")
+	if err != nil {
+		return fmt.Errorf("invalid Strict-Transport-Security header: %v", err)
+	}
+
+	raw, ok := directives["max-age"]
+	if !ok {
+		return fmt.Errorf("Strict-Transport-Security has no max-age directive")
+	}
+
+	maxAge, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || maxAge < 0 {
+		return fmt.Errorf("Strict-Transport-Security has invalid max-age: %q", raw)
+	}
+
+	if maxAge < int64(ex.Config.HSTSMinMaxAge) {
+		return fmt.Errorf("Strict-Transport-Security max-age %d is below %d", maxAge, ex.Config.HSTSMinMaxAge)
+	}
+
+	if _, ok := directives["includesubdomains"]; ex.Config.HSTSSubdomains && !ok {
+		return fmt.Errorf("Strict-Transport-Security is missing includeSubDomains")
+	}
+
+	if _, ok := directives["preload"]; ex.Config.HSTSPreload && !ok {
+		return fmt.Errorf("Strict-Transport-Security is missing preload")
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseDirectives(t *testing.T) {
+	tests := []struct {
+		header    string
+		separator string
+		want      map[string]string
+		err       string
+	}{
+		{"max-age=31536000; includeSubDomains; preload", ";", map[string]string{"max-age": "31536000", "includesubdomains": "", "preload": ""}, ""},
+		{`max-age="600"`, ";", map[string]string{"max-age": "600"}, ""},
+		{"no-cache, Max-Age=0 ,", ",", map[string]string{"no-cache": "", "max-age": "0"}, ""},
+		{"max-age = 600 ;; preload", ";", map[string]string{"max-age": "600", "preload": ""}, ""},
+		{`max-age="`, ";", map[string]string{"max-age": `"`}, ""},
+		{"", ";", map[string]string{}, ""},
+		{"max-age=1; max-age=2", ";", nil, `directive "max-age" appears more than once`},
+		{"Preload; preload", ";", nil, `directive "preload" appears more than once`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, err := parseDirectives(tt.header, tt.separator)
+			checkError(t, err, tt.err)
+			if tt.err == "" && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDirectives(%q) = %v; want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHSTSMatcher(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []string
+		conf   map[string]any
+		want   string
+	}{
+		{"valid", []string{"max-age=31536000; includeSubDomains; preload"}, map[string]any{"hsts_min_max_age": 31536000, "hsts_include_subdomains": true, "hsts_preload": true}, ""},
+		{"missing", nil, nil, "no Strict-Transport-Security header"},
+		{"no max-age", []string{"includeSubDomains"}, nil, "no max-age directive"},
+		{"bad max-age", []string{"max-age=-1"}, nil, "invalid max-age"},
+		{"quoted max-age", []string{`max-age="31536000"`}, map[string]any{"hsts_min_max_age": 3600}, ""},
+		{"case-insensitive names", []string{"Max-Age=60; INCLUDESUBDOMAINS"}, map[string]any{"hsts_include_subdomains": true}, ""},
+		{"duplicate directive", []string{"max-age=60; max-age=120"}, nil, "invalid Strict-Transport-Security header"},
+		{"short max-age", []string{"max-age=60"}, map[string]any{"hsts_min_max_age": 3600}, "max-age 60 is below 3600"},
+		{"no subdomains", []string{"max-age=60"}, map[string]any{"hsts_include_subdomains": true}, "missing includeSubDomains"},
+		{"no preload", []string{"max-age=60"}, map[string]any{"hsts_preload": true}, "missing preload"},
+		{"only first field", []string{"max-age=60", "max-age=31536000"}, map[string]any{"hsts_min_max_age": 3600}, "below 3600"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for _, value := range tt.header {
+					w.Header().Add("Strict-Transport-Security", value)
+				}
+			}))
+			defer server.Close()
+
+			conf := map[string]any{"match_type": "hsts"}
+			for key, value := range tt.conf {
+				conf[key] = value
+			}
+
+			err := runAgainst(t, server, conf)
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestHSTSRequiresHTTPS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Strict-Transport-Security", "max-age=60")
+	}))
+	defer server.Close()
+
+	err := runAgainst(t, server, map[string]any{"match_type": "hsts"})
+	checkError(t, err, "hsts requires an https url")
+}
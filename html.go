@@ -0,0 +1,311 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// htmlElement is a start tag found in an HTML document. Tag and attribute
+// names are lower-cased and attribute values unescaped.
+type htmlElement struct {
+	Tag   string
+	Attrs map[string]string
+}
+
+// Attr returns the value of the named attribute and whether it is present.
+func (e htmlElement) Attr(name string) (string, bool) {
+	value, ok := e.Attrs[name]
+	return value, ok
+}
+
+// scanHTML returns the start tags of doc in document order. It is a lenient
+// scanner rather than a full parser: checks only ever need to find elements
+// by tag and attribute, and real pages are too often malformed for a strict
+// parser. Comments, doctypes and the contents of script and style elements
+// are skipped.
+func scanHTML(doc string) []htmlElement {
+	elements := []htmlElement{}
+
+	for i := 0; i < len(doc); {
+		start := strings.IndexByte(doc[i:], '<')
+		if start < 0 {
+			break
+		}
+		i += start
+
+		switch {
+		case strings.HasPrefix(doc[i:], "<!--"):
+			end := strings.Index(doc[i+4:], "-->")
+			if end < 0 {
+				return elements
+			}
+			i += 4 + end + 3
+			continue
+		case strings.HasPrefix(doc[i:], "<!"), strings.HasPrefix(doc[i:], "</"), strings.HasPrefix(doc[i:], "<?"):
+			end := strings.IndexByte(doc[i:], '>')
+			if end < 0 {
+				return elements
+			}
+			i += end + 1
+			continue
+		}
+
+		element, next, ok := scanTag(doc, i+1)
+		if !ok {
+			i++
+			continue
+		}
+		elements = append(elements, element)
+		i = next
+
+		if element.Tag == "script" || element.Tag == "style" {
+			end := indexFold(doc[i:], "</"+element.Tag)
+			if end < 0 {
+				return elements
+			}
+			i += end
+		}
+	}
+
+	return elements
+}
+
+// scanTag parses a start tag whose name begins at doc[i] and returns the
+// element and the offset just past its closing '>'.
+func scanTag(doc string, i int) (htmlElement, int, bool) {
+	nameEnd := i
+	for nameEnd < len(doc) && isNameByte(doc[nameEnd]) {
+		nameEnd++
+	}
+	if nameEnd == i || !isLetter(doc[i]) {
+		return htmlElement{}, 0, false
+	}
+
+	element := htmlElement{Tag: strings.ToLower(doc[i:nameEnd]), Attrs: map[string]string{}}
+	i = nameEnd
+
+	for i < len(doc) {
+		for i < len(doc) && (isSpace(doc[i]) || doc[i] == '/') {
+			i++
+		}
+		if i >= len(doc) {
+			break
+		}
+		if doc[i] == '>' {
+			return element, i + 1, true
+		}
+
+		keyStart := i
+		for i < len(doc) && !isSpace(doc[i]) && doc[i] != '=' && doc[i] != '>' && doc[i] != '/' {
+			i++
+		}
+		key := strings.ToLower(doc[keyStart:i])
+
+		for i < len(doc) && isSpace(doc[i]) {
+			i++
+		}
+
+		value := ""
+		if i < len(doc) && doc[i] == '=' {
+			i++
+			for i < len(doc) && isSpace(doc[i]) {
+				i++
+			}
+
+			if i < len(doc) && (doc[i] == '"' || doc[i] == '\'') {
+				quote := doc[i]
+				end := strings.IndexByte(doc[i+1:], quote)
+				if end < 0 {
+					return htmlElement{}, 0, false
+				}
+				value = doc[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				valueStart := i
+				for i < len(doc) && !isSpace(doc[i]) && doc[i] != '>' {
+					i++
+				}
+				value = doc[valueStart:i]
+			}
+		}
+
+		if _, dup := element.Attrs[key]; !dup && key != "" {
+			element.Attrs[key] = html.UnescapeString(value)
+		}
+	}
+
+	return htmlElement{}, 0, false
+}
+
+// indexFold is strings.Index ignoring ASCII case. Unlike searching a
+// lower-cased copy, its offsets are always valid in s.
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		match := true
+		for j := 0; j < len(substr); j++ {
+			if lowerASCII(s[i+j]) != lowerASCII(substr[j]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func lowerASCII(b byte) byte {
+	if b >= 'A' && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+
+	return b
+}
+
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isNameByte(b byte) bool {
+	return isLetter(b) || (b >= '0' && b <= '9') || b == '-' || b == ':'
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
+
+// selector is a single compound CSS selector such as form#login.wide[action].
+// Combinators are not supported.
+type selector struct {
+	tag     string
+	id      string
+	classes []string
+	attrs   []selectorAttr
+}
+
+type selectorAttr struct {
+	name     string
+	value    string
+	hasValue bool
+}
+
+func parseSelector(raw string) (selector, error) {
+	sel := selector{}
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.ContainsAny(raw, " >+~,") {
+		return sel, fmt.Errorf("selector must be a single compound selector like \"form#login[action]\"; got: %q", raw)
+	}
+
+	i := 0
+	for i < len(raw) && isNameByte(raw[i]) {
+		i++
+	}
+	sel.tag = strings.ToLower(raw[:i])
+
+	for i < len(raw) {
+		switch raw[i] {
+		case '#', '.':
+			kind := raw[i]
+			i++
+			start := i
+			for i < len(raw) && raw[i] != '#' && raw[i] != '.' && raw[i] != '[' {
+				i++
+			}
+			if start == i {
+				return sel, fmt.Errorf("empty id or class in selector: %q", raw)
+			}
+			if kind == '#' {
+				sel.id = raw[start:i]
+			} else {
+				sel.classes = append(sel.classes, raw[start:i])
+			}
+		case '[':
+			end := strings.IndexByte(raw[i:], ']')
+			if end < 0 {
+				return sel, fmt.Errorf("unterminated attribute in selector: %q", raw)
+			}
+			body := raw[i+1 : i+end]
+			name, value, hasValue := strings.Cut(body, "=")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				return sel, fmt.Errorf("empty attribute name in selector: %q", raw)
+			}
+			sel.attrs = append(sel.attrs, selectorAttr{
+				name:     name,
+				value:    strings.Trim(strings.TrimSpace(value), `"'`),
+				hasValue: hasValue,
+			})
+			i += end + 1
+		default:
+			return sel, fmt.Errorf("unexpected %q in selector: %q", raw[i], raw)
+		}
+	}
+
+	return sel, nil
+}
+
+func (s selector) matches(e htmlElement) bool {
+	if s.tag != "" && s.tag != e.Tag {
+		return false
+	}
+
+	if s.id != "" && e.Attrs["id"] != s.id {
+		return false
+	}
+
+	classes := strings.Fields(e.Attrs["class"])
+	for _, class := range s.classes {
+		found := false
+		for _, have := range classes {
+			if have == class {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for _, attr := range s.attrs {
+		value, ok := e.Attrs[attr.name]
+		if !ok || (attr.hasValue && value != attr.value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// htmlElementMatcher expects the HTML body to contain an element matching the
+// selector in expected_output, e.g. "#login-form" or "input[name=csrf]".
+type htmlElementMatcher struct{}
+
+func (htmlElementMatcher) ValidateConfig(conf Schema) error {
+	_, err := parseSelector(conf.ExpectedOutput)
+	return err
+}
+
+func (htmlElementMatcher) Match(ctx context.Context, ex *Exchange) error {
+	sel, err := parseSelector(ex.Config.ExpectedOutput)
+	if err != nil {
+		return err
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	for _, element := range scanHTML(string(body)) {
+		if sel.matches(element) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no element matching %q found in response body", ex.Config.ExpectedOutput)
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestScanHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want []htmlElement
+	}{
+		{"attributes", `<A HREF="/x?a=1&amp;b=2" data-x=y checked>`, []htmlElement{
+			{Tag: "a", Attrs: map[string]string{"href": "/x?a=1&b=2", "data-x": "y", "checked": ""}},
+		}},
+		{"skips comments and script", `<!-- <p id=c> --><script>if (a<b) document.write("<p id=s>")</SCRIPT><p id=real>`, []htmlElement{
+			{Tag: "script", Attrs: map[string]string{}},
+			{Tag: "p", Attrs: map[string]string{"id": "real"}},
+		}},
+		// İ lower-cases to three bytes, so offsets into a lower-cased copy
+		// would land inside the script.
+		{"non-ascii before script", `<p>İİİİİİ</p><style>p{}</style><script>"<p id=s>"</script><p id=real>`, []htmlElement{
+			{Tag: "p", Attrs: map[string]string{}},
+			{Tag: "style", Attrs: map[string]string{}},
+			{Tag: "script", Attrs: map[string]string{}},
+			{Tag: "p", Attrs: map[string]string{"id": "real"}},
+		}},
+		{"duplicate attribute", `<input name=a name=b>`, []htmlElement{
+			{Tag: "input", Attrs: map[string]string{"name": "a"}},
+		}},
+		{"unterminated script", `<script>var a = 1;`, []htmlElement{
+			{Tag: "script", Attrs: map[string]string{}},
+		}},
+		{"not a tag", `a < b and 1<2`, []htmlElement{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scanHTML(tt.doc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scanHTML(%q) = %v; want %v", tt.doc, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		raw     string
+		element htmlElement
+		match   bool
+		err     string
+	}{
+		{"#login", htmlElement{Tag: "form", Attrs: map[string]string{"id": "login"}}, true, ""},
+		{"FORM#login.wide", htmlElement{Tag: "form", Attrs: map[string]string{"id": "login", "class": "wide dark"}}, true, ""},
+		{"form.wide.tall", htmlElement{Tag: "form", Attrs: map[string]string{"class": "wide"}}, false, ""},
+		{"input[name=csrf]", htmlElement{Tag: "input", Attrs: map[string]string{"name": "csrf"}}, true, ""},
+		{`input[name="csrf"][value]`, htmlElement{Tag: "input", Attrs: map[string]string{"name": "csrf"}}, false, ""},
+		{"div > p", htmlElement{}, false, "single compound selector"},
+		{"#", htmlElement{}, false, "empty id or class"},
+		{"a[href", htmlElement{}, false, "unterminated attribute"},
+		{"a[=x]", htmlElement{}, false, "empty attribute name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			sel, err := parseSelector(tt.raw)
+			checkError(t, err, tt.err)
+			if tt.err == "" && sel.matches(tt.element) != tt.match {
+				t.Errorf("%q matches %v = %v; want %v", tt.raw, tt.element, !tt.match, tt.match)
+			}
+		})
+	}
+}
+
+func TestHTMLElementMatcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<p>Ünïcödé İstanbul</p><script>"<form id=login>"</script><form id="signup"></form>`))
+	}))
+	defer server.Close()
+
+	checkError(t, runAgainst(t, server, map[string]any{"match_type": "htmlElement", "expected_output": "form#signup"}), "")
+	checkError(t, runAgainst(t, server, map[string]any{"match_type": "htmlElement", "expected_output": "form#login"}), "form#login")
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// setIdempotencyKey gives req a fresh random Idempotency-Key.
+func setIdempotencyKey(conf Schema, req *http.Request) error {
+	if !conf.IdempotencyKey {
+		return nil
+	}
+
+	key, err := nonce()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Idempotency-Key", key)
+
+	return nil
+}
+
+// assertIdempotentReplay resends the exact request and expects the same
+// resource back: the same status, and the same Location or, without one, the
+// same body.
+func assertIdempotentReplay(ctx context.Context, ex *Exchange) error {
+	if !ex.Config.IdempotencyKey || !ex.Config.IdempotencyReplay {
+		return nil
+	}
+
+	// A rebuilt request would carry a new nonce, timestamp and signature.
+	req := ex.Request.Clone(ctx)
+	if ex.RequestBody != nil {
+		req.Body = io.NopCloser(bytes.NewReader(ex.RequestBody))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(ex.RequestBody)), nil
+		}
+		req.ContentLength = int64(len(ex.RequestBody))
+	}
+
+	resp, err := ex.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("idempotent replay: encounted error while making request: %v", err.Error())
+	}
+	defer drainBody(resp.Body)
+
+	if resp.StatusCode != ex.Response.StatusCode {
+		return fmt.Errorf("idempotent replay: expected status code: %d; got: %d", ex.Response.StatusCode, resp.StatusCode)
+	}
+
+	if location := ex.Response.Header.Get("Location"); location != "" {
+		if resp.Header.Get("Location") != location {
+			return fmt.Errorf("idempotent replay: expected Location %s; got: %s", location, resp.Header.Get("Location"))
+		}
+		return nil
+	}
+
+	first, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	second, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("idempotent replay: encountered error while reading response body: %v", err)
+	}
+
+	if bytes.Equal(first, second) {
+		return nil
+	}
+
+	a, errA := decodeJSON(first)
+	b, errB := decodeJSON(second)
+	if errA == nil && errB == nil && jsonEqual(a, b) {
+		return nil
+	}
+
+	return fmt.Errorf("idempotent replay returned a different response body")
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestIdempotentReplaySendsSameRequest(t *testing.T) {
+	var mu sync.Mutex
+	bodies := []string{}
+	keys := []string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		mu.Unlock()
+
+		w.Header().Set("Location", "/orders/1")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	config, _ := json.Marshal(map[string]any{
+		"url":                server.URL + "/orders",
+		"verb":               "POST",
+		"content_type":       "application/json",
+		"body":               `{"nonce": "{{nonce}}", "at": {{timestampMs}}}`,
+		"expected_output":    "201",
+		"idempotency_key":    true,
+		"idempotency_replay": true,
+	})
+
+	err := New().Run(context.Background(), string(config))
+	if err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("server saw %d requests; want 2", len(bodies))
+	}
+	if bodies[0] != bodies[1] {
+		t.Errorf("replayed body %q differs from the original %q", bodies[1], bodies[0])
+	}
+	if keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("Idempotency-Key %q then %q; want the same key twice", keys[0], keys[1])
+	}
+}
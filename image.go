@@ -0,0 +1,72 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"slices"
+)
+
+// maxImagePixels bounds the dimensions a body may declare before it is fully
+// decoded, since decoding allocates for every pixel.
+const maxImagePixels = 64 << 20
+
+// imageMatcher expects the body to decode fully as a PNG, JPEG or GIF,
+// optionally of image_format and at least image_min_width by
+// image_min_height pixels.
+type imageMatcher struct{}
+
+func (imageMatcher) ValidateConfig(conf Schema) error {
+	if !slices.Contains([]string{"any", "png", "jpeg", "gif"}, conf.ImageFormat) {
+		return fmt.Errorf("invalid image_format provided: %v", conf.ImageFormat)
+	}
+
+	if conf.ImageMinWidth < 0 {
+		return fmt.Errorf("image_min_width must not be negative; got: %d", conf.ImageMinWidth)
+	}
+
+	if conf.ImageMinHeight < 0 {
+		return fmt.Errorf("image_min_height must not be negative; got: %d", conf.ImageMinHeight)
+	}
+
+	return nil
+}
+
+func (imageMatcher) Match(ctx context.Context, ex *Exchange) error {
+	conf := ex.Config
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	header, _, err := image.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("response body is not a decodable image: %v", err)
+	}
+
+	if int64(header.Width)*int64(header.Height) > maxImagePixels {
+		return fmt.Errorf("image of %dx%d exceeds the %d pixel limit", header.Width, header.Height, maxImagePixels)
+	}
+
+	// A full decode as well, so truncated images fail.
+	img, format, err := image.Decode(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("response body is not a decodable image: %v", err)
+	}
+
+	if conf.ImageFormat != "any" && format != conf.ImageFormat {
+		return fmt.Errorf("expected %s image; got: %s", conf.ImageFormat, format)
+	}
+
+	size := img.Bounds().Size()
+	if size.X < conf.ImageMinWidth || size.Y < conf.ImageMinHeight {
+		return fmt.Errorf("expected image of at least %dx%d; got: %dx%d", conf.ImageMinWidth, conf.ImageMinHeight, size.X, size.Y)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height)))
+	if err != nil {
+		t.Fatalf("png.Encode() = %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+// withDimensions rewrites the IHDR chunk of a PNG to declare width by height
+// without adding any pixel data.
+func withDimensions(data []byte, width, height uint32) []byte {
+	out := bytes.Clone(data)
+	// Signature (8), then IHDR length (4) and type (4), then the fields.
+	binary.BigEndian.PutUint32(out[16:], width)
+	binary.BigEndian.PutUint32(out[20:], height)
+	binary.BigEndian.PutUint32(out[29:], crc32.ChecksumIEEE(out[12:29]))
+	return out
+}
+
+func TestImageMatcher(t *testing.T) {
+	small := encodePNG(t, 4, 3)
+
+	tests := []struct {
+		name string
+		body []byte
+		conf map[string]any
+		want string
+	}{
+		{"valid", small, nil, ""},
+		{"min size met", small, map[string]any{"image_min_width": 4, "image_min_height": 3}, ""},
+		{"too small", small, map[string]any{"image_min_width": 5}, "expected image of at least 5x0"},
+		{"wrong format", small, map[string]any{"image_format": "gif"}, "expected gif image"},
+		{"truncated", small[:len(small)-20], nil, "not a decodable image"},
+		{"not an image", []byte("hello"), nil, "not a decodable image"},
+		{"too many pixels", withDimensions(small, 1<<16, 1<<16), nil, "pixel limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write(tt.body)
+			}))
+			defer server.Close()
+
+			doc := map[string]any{"url": server.URL, "match_type": "image"}
+			for key, value := range tt.conf {
+				doc[key] = value
+			}
+			config, _ := json.Marshal(doc)
+
+			err := New().Run(context.Background(), string(config))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Run() = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Run() = %v; want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
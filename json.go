@@ -0,0 +1,263 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+)
+
+// validJSONMatcher expects the body to be a single well-formed JSON value,
+// optionally of the top-level type named by json_type.
+type validJSONMatcher struct{}
+
+func (validJSONMatcher) ValidateConfig(conf Schema) error {
+	if !slices.Contains([]string{"any", "object", "array", "string", "number", "boolean", "null"}, conf.JSONType) {
+		return fmt.Errorf("invalid json_type provided: %v", conf.JSONType)
+	}
+
+	return nil
+}
+
+func (validJSONMatcher) Match(ctx context.Context, ex *Exchange) error {
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	value, err := decodeJSON(body)
+	if err != nil {
+		return fmt.Errorf("response body is not valid json: %v", err)
+	}
+
+	if ex.Config.JSONType != "any" && jsonType(value) != ex.Config.JSONType {
+		return fmt.Errorf("expected top-level json %s; got: %s", ex.Config.JSONType, jsonType(value))
+	}
+
+	return nil
+}
+
+// decodeJSON parses body as exactly one JSON value, keeping numbers exact.
+func decodeJSON(body []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("trailing data after top-level value")
+	}
+
+	return value, nil
+}
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
+
+// jsonEqual compares two decoded JSON values structurally. Object key order
+// is irrelevant and numbers compare by value, so 1 equals 1.0.
+func jsonEqual(a any, b any) bool {
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, value := range a {
+			other, ok := b[key]
+			if !ok || !jsonEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		return ok && numbersEqual(a, b)
+	default:
+		return a == b
+	}
+}
+
+func numbersEqual(a json.Number, b json.Number) bool {
+	x, okX := new(big.Rat).SetString(a.String())
+	y, okY := new(big.Rat).SetString(b.String())
+	if !okX || !okY {
+		return a == b
+	}
+
+	return x.Cmp(y) == 0
+}
+
+// jsonEqualsMatcher expects the body to be the JSON document in
+// expected_output, ignoring key order and insignificant whitespace.
+type jsonEqualsMatcher struct{}
+
+func (jsonEqualsMatcher) ValidateConfig(conf Schema) error {
+	if conf.ExpectedOutput == "" {
+		return fmt.Errorf("expected_output must be provided; got: %v", conf.ExpectedOutput)
+	}
+
+	_, err := decodeJSON([]byte(conf.ExpectedOutput))
+	if err != nil {
+		return fmt.Errorf("expected_output is not valid json: %v", err)
+	}
+
+	return nil
+}
+
+func (jsonEqualsMatcher) Match(ctx context.Context, ex *Exchange) error {
+	expected, err := decodeJSON([]byte(ex.Config.ExpectedOutput))
+	if err != nil {
+		return fmt.Errorf("expected_output is not valid json: %v", err)
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	actual, err := decodeJSON(body)
+	if err != nil {
+		return fmt.Errorf("response body is not valid json: %v", err)
+	}
+
+	if !jsonEqual(expected, actual) {
+		return fmt.Errorf("response json does not equal expected output")
+	}
+
+	return nil
+}
+
+// jsonSubsetOf reports whether want is contained in have: objects may carry
+// extra keys, arrays may carry extra elements in any order, and scalars must
+// be equal. Each wanted array element needs an element of its own.
+func jsonSubsetOf(want any, have any) bool {
+	switch want := want.(type) {
+	case map[string]any:
+		have, ok := have.(map[string]any)
+		if !ok {
+			return false
+		}
+		for key, value := range want {
+			other, ok := have[key]
+			if !ok || !jsonSubsetOf(value, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		have, ok := have.([]any)
+		if !ok || len(want) > len(have) {
+			return false
+		}
+		return matchElements(want, have)
+	default:
+		return jsonEqual(want, have)
+	}
+}
+
+// matchElements reports whether every element of want can be paired with a
+// distinct element of have containing it. Taking the first fit is not enough,
+// since it may be the only fit for a later element, so pairs are found by
+// augmenting paths.
+func matchElements(want []any, have []any) bool {
+	fits := make([][]bool, len(want))
+	for i := range want {
+		fits[i] = make([]bool, len(have))
+		for j := range have {
+			fits[i][j] = jsonSubsetOf(want[i], have[j])
+		}
+	}
+
+	owner := make([]int, len(have))
+	for j := range owner {
+		owner[j] = -1
+	}
+
+	var assign func(i int, seen []bool) bool
+	assign = func(i int, seen []bool) bool {
+		for j := range have {
+			if !fits[i][j] || seen[j] {
+				continue
+			}
+			seen[j] = true
+			if owner[j] < 0 || assign(owner[j], seen) {
+				owner[j] = i
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := range want {
+		if !assign(i, make([]bool, len(have))) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// jsonSubsetMatcher expects the JSON document in expected_output to be
+// contained in the body, ignoring anything the response adds.
+type jsonSubsetMatcher struct{}
+
+func (jsonSubsetMatcher) ValidateConfig(conf Schema) error {
+	return jsonEqualsMatcher{}.ValidateConfig(conf)
+}
+
+func (jsonSubsetMatcher) Match(ctx context.Context, ex *Exchange) error {
+	expected, err := decodeJSON([]byte(ex.Config.ExpectedOutput))
+	if err != nil {
+		return fmt.Errorf("expected_output is not valid json: %v", err)
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	actual, err := decodeJSON(body)
+	if err != nil {
+		return fmt.Errorf("response body is not valid json: %v", err)
+	}
+
+	if !jsonSubsetOf(expected, actual) {
+		return fmt.Errorf("response json does not contain expected output")
+	}
+
+	return nil
+}
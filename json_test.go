@@ -0,0 +1,200 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		body     string
+		jsonType string
+		err      string
+	}{
+		{`{"a": 1}`, "object", ""},
+		{` [1, 2] `, "array", ""},
+		{`"text"`, "string", ""},
+		{`12345678901234567890`, "number", ""},
+		{`false`, "boolean", ""},
+		{`null`, "null", ""},
+		{"{\"a\": 1}\n", "object", ""},
+		{`{"a": 1} {"b": 2}`, "", "trailing data"},
+		{`{"a": 1}}`, "", "trailing data"},
+		{`{"a": }`, "", "invalid character"},
+		{`{"a": 1`, "", "unexpected EOF"},
+		{``, "", "EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			value, err := decodeJSON([]byte(tt.body))
+			checkError(t, err, tt.err)
+			if tt.err == "" && jsonType(value) != tt.jsonType {
+				t.Errorf("jsonType(%s) = %s; want %s", tt.body, jsonType(value), tt.jsonType)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONKeepsLargeNumbers(t *testing.T) {
+	value, err := decodeJSON([]byte(`{"id": 12345678901234567890}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := scalarString(value.(map[string]any)["id"]); got != "12345678901234567890" {
+		t.Errorf("id = %s; want 12345678901234567890", got)
+	}
+}
+
+func TestValidJSONMatcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		jsonType string
+		want     string
+	}{
+		{"any", `[1, 2]`, "any", ""},
+		{"object", `{"status": "ok"}`, "object", ""},
+		{"null", `null`, "null", ""},
+		{"wrong type", `{"status": "ok"}`, "array", "expected top-level json array; got: object"},
+		{"html error page", `<html>502 Bad Gateway</html>`, "any", "response body is not valid json"},
+		{"truncated", `{"items": [1, 2`, "any", "response body is not valid json"},
+		{"two documents", `{"a": 1}{"a": 2}`, "object", "trailing data after top-level value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			err := runAgainst(t, server, map[string]any{"match_type": "validJson", "json_type": tt.jsonType})
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestJSONEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{`{"a": 1, "b": [1, 2]}`, `{"b": [1, 2], "a": 1}`, true},
+		{`{"a": {"x": 1, "y": 2}}`, `{"a": {"y": 2, "x": 1}}`, true},
+		{`1`, `1.0`, true},
+		{`1e2`, `100`, true},
+		{`-0`, `0`, true},
+		{`0.3`, `0.30000000000000004`, false},
+		{`12345678901234567890`, `12345678901234567891`, false},
+		{`[1, 2]`, `[2, 1]`, false},
+		{`{"a": 1}`, `{"a": 1, "b": 2}`, false},
+		{`{"a": null}`, `{}`, false},
+		{`{"a": null}`, `{"b": null}`, false},
+		{`"1"`, `1`, false},
+		{`true`, `"true"`, false},
+		{`null`, `null`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			a, _ := decodeJSON([]byte(tt.a))
+			b, _ := decodeJSON([]byte(tt.b))
+			if got := jsonEqual(a, b); got != tt.want {
+				t.Errorf("jsonEqual(%s, %s) = %v; want %v", tt.a, tt.b, got, tt.want)
+			}
+			if got := jsonEqual(b, a); got != tt.want {
+				t.Errorf("jsonEqual(%s, %s) = %v; want %v", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONEqualsMatcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "ok",
+			"items": [1, 2], "count": 2}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		expected string
+		want     string
+	}{
+		{"reordered", `{"count": 2.0, "items": [1, 2], "status": "ok"}`, ""},
+		{"missing key", `{"status": "ok", "items": [1, 2]}`, "response json does not equal expected output"},
+		{"array order", `{"status": "ok", "items": [2, 1], "count": 2}`, "response json does not equal expected output"},
+		{"invalid expected", `{"status": }`, "expected_output is not valid json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runAgainst(t, server, map[string]any{"match_type": "jsonEquals", "expected_output": tt.expected})
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestJSONSubsetOf(t *testing.T) {
+	tests := []struct {
+		want, have string
+		subset     bool
+	}{
+		{`{"a": 1}`, `{"a": 1.0, "b": 2}`, true},
+		{`{"a": {"b": true}}`, `{"a": {"b": true, "c": null}}`, true},
+		{`{"a": 1}`, `{"b": 1}`, false},
+		{`{"a": [1]}`, `{"a": 1}`, false},
+		{`[2, 1]`, `[1, 2, 3]`, true},
+		{`[1, 1]`, `[1, 2]`, false},
+		{`[1, 1]`, `[1, 2, 1]`, true},
+		{`[]`, `[]`, true},
+		{`[1]`, `[]`, false},
+		// The first wanted element fits both, the second only the first;
+		// taking the first fit greedily would leave the second unmatched.
+		{`[{"a": 1}, {"a": 1, "b": 2}]`, `[{"a": 1, "b": 2}, {"a": 1}]`, true},
+		{`[[1], [1, 2]]`, `[[1, 2, 3], [1, 3]]`, true},
+		{`[{"a": 1}, {"a": 1}, {"b": 1}]`, `[{"a": 1, "b": 1}, {"a": 1}]`, false},
+		{`"x"`, `"x"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want+" in "+tt.have, func(t *testing.T) {
+			want, err := decodeJSON([]byte(tt.want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			have, err := decodeJSON([]byte(tt.have))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := jsonSubsetOf(want, have); got != tt.subset {
+				t.Errorf("jsonSubsetOf(%s, %s) = %v; want %v", tt.want, tt.have, got, tt.subset)
+			}
+		})
+	}
+}
+
+func TestJSONSubsetMatcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "ok", "users": [{"name": "a", "admin": true}, {"name": "a"}]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		expected string
+		want     string
+	}{
+		{`{"users": [{"name": "a"}, {"name": "a", "admin": true}]}`, ""},
+		{`{"status": "down"}`, "response json does not contain expected output"},
+		{`{"status": }`, "expected_output is not valid json"},
+	}
+
+	for _, tt := range tests {
+		err := runAgainst(t, server, map[string]any{"match_type": "jsonSubset", "expected_output": tt.expected})
+		checkError(t, err, tt.want)
+	}
+}
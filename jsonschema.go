@@ -0,0 +1,93 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// requiredKeys are the keys a config must set. decodeConfig fills every other
+// key from its default, so any document valid under JSONSchema decodes.
+var requiredKeys = []string{"url", "expected_output"}
+
+// JSONSchema describes the config accepted by Run as a JSON Schema document
+// (draft 2020-12), including defaults, enums and field descriptions. The
+// match_type and auth enums list every registered matcher and provider.
+func JSONSchema() ([]byte, error) {
+	properties := map[string]any{}
+
+	t := reflect.TypeOf(Schema{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		key := field.Tag.Get("key")
+		if key == "" {
+			continue
+		}
+
+		property, err := describeField(field)
+		if err != nil {
+			return nil, err
+		}
+
+		properties[key] = property
+	}
+
+	properties["match_type"].(map[string]any)["enum"] = Matchers()
+	properties["auth"].(map[string]any)["enum"] = AuthProviders()
+
+	return json.MarshalIndent(map[string]any{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                "http",
+		"type":                 "object",
+		"properties":           properties,
+		"required":             requiredKeys,
+		"additionalProperties": false,
+	}, "", "  ")
+}
+
+func describeField(field reflect.StructField) (map[string]any, error) {
+	property := map[string]any{}
+
+	if description := field.Tag.Get("description"); description != "" {
+		property["description"] = description
+	}
+
+	if enum := field.Tag.Get("enum"); enum != "" {
+		property["enum"] = strings.Split(enum, ",")
+	}
+
+	def, hasDefault := field.Tag.Lookup("default")
+
+	switch field.Type.Kind() {
+	case reflect.String:
+		property["type"] = "string"
+		if hasDefault {
+			property["default"] = def
+		}
+	case reflect.Bool:
+		property["type"] = "boolean"
+		if hasDefault {
+			value, err := strconv.ParseBool(def)
+			if err != nil {
+				return nil, fmt.Errorf("invalid default for %s: %v", field.Name, err)
+			}
+			property["default"] = value
+		}
+	case reflect.Int:
+		property["type"] = "integer"
+		if hasDefault {
+			value, err := strconv.Atoi(def)
+			if err != nil {
+				return nil, fmt.Errorf("invalid default for %s: %v", field.Name, err)
+			}
+			property["default"] = value
+		}
+	default:
+		return nil, fmt.Errorf("unsupported field type for %s: %v", field.Name, field.Type)
+	}
+
+	return property, nil
+}
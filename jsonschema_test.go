@@ -0,0 +1,71 @@
+package http
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONSchemaRequiredKeysSuffice(t *testing.T) {
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema() = %v", err)
+	}
+
+	doc := struct {
+		Required   []string                  `json:"required"`
+		Properties map[string]map[string]any `json:"properties"`
+	}{}
+	err = json.Unmarshal(data, &doc)
+	if err != nil {
+		t.Fatalf("JSONSchema() is not valid json: %v", err)
+	}
+
+	values := map[string]any{
+		"url":             "http://example.com/",
+		"expected_output": "200",
+	}
+
+	config := map[string]any{}
+	for _, key := range doc.Required {
+		if _, ok := doc.Properties[key]; !ok {
+			t.Errorf("required key %s has no property", key)
+		}
+		value, ok := values[key]
+		if !ok {
+			t.Fatalf("no test value for required key %s", key)
+		}
+		config[key] = value
+	}
+
+	minimal, _ := json.Marshal(config)
+	err = Validate(string(minimal))
+	if err != nil {
+		t.Fatalf("Validate(only required keys) = %v; want nil", err)
+	}
+}
+
+func TestJSONSchemaDefaultsDecode(t *testing.T) {
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema() = %v", err)
+	}
+
+	doc := struct {
+		Properties map[string]map[string]any `json:"properties"`
+	}{}
+	_ = json.Unmarshal(data, &doc)
+
+	// Every default the schema publishes must be a value the decoder accepts.
+	config := map[string]any{"url": "http://example.com/", "expected_output": "200"}
+	for key, property := range doc.Properties {
+		if def, ok := property["default"]; ok {
+			config[key] = def
+		}
+	}
+
+	full, _ := json.Marshal(config)
+	_, err = decodeConfig(string(full))
+	if err != nil {
+		t.Fatalf("decodeConfig(all defaults) = %v", err)
+	}
+}
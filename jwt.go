@@ -0,0 +1,411 @@
+package http
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// validateJWT checks the key and claims jwt auth mints its token from.
+func validateJWT(conf Schema) error {
+	if conf.JWTKey == "" {
+		return fmt.Errorf("jwt auth requires jwt_key to be provided")
+	}
+
+	if conf.JWTAlgorithm == "RS256" {
+		_, err := rsaPrivateKey(conf.JWTKey)
+		if err != nil {
+			return err
+		}
+	} else if conf.JWTAlgorithm != "HS256" {
+		return fmt.Errorf("invalid jwt_algorithm provided: %v", conf.JWTAlgorithm)
+	}
+
+	if conf.JWTTTLSeconds <= 0 {
+		return fmt.Errorf("jwt_ttl_seconds must be positive; got: %d", conf.JWTTTLSeconds)
+	}
+
+	if strings.Contains(conf.JWTClaims, "{{") {
+		_, err := parseTemplate("jwt_claims", conf.JWTClaims)
+		return err
+	}
+
+	if conf.JWTClaims != "" {
+		claims := map[string]any{}
+		err := json.Unmarshal([]byte(conf.JWTClaims), &claims)
+		if err != nil {
+			return fmt.Errorf("jwt_claims must be a JSON object; %q", err)
+		}
+	}
+
+	return nil
+}
+
+// authJWT mints a token from jwt_claims, signed with jwt_key, and sends it as
+// a bearer token. iat and exp are added unless the claims set them, so every
+// request carries a token that expires jwt_ttl_seconds later.
+func authJWT(ctx context.Context, conf Schema, req *http.Request) error {
+	now := time.Now()
+
+	raw, err := render(ctx, "jwt_claims", conf.JWTClaims, now)
+	if err != nil {
+		return err
+	}
+
+	claims := map[string]any{}
+	if raw != "" {
+		err = json.Unmarshal([]byte(raw), &claims)
+		if err != nil {
+			return fmt.Errorf("jwt_claims must be a JSON object; %q", err)
+		}
+	}
+
+	if _, ok := claims["iat"]; !ok {
+		claims["iat"] = now.Unix()
+	}
+	if _, ok := claims["exp"]; !ok {
+		claims["exp"] = now.Add(time.Duration(conf.JWTTTLSeconds) * time.Second).Unix()
+	}
+
+	token, err := mintJWT(conf.JWTAlgorithm, conf.JWTKey, claims)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func mintJWT(algorithm string, key string, claims map[string]any) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": algorithm, "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("encountered error while encoding jwt claims: %v", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	var signature []byte
+	switch algorithm {
+	case "HS256":
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(signingInput))
+		signature = mac.Sum(nil)
+	case "RS256":
+		private, err := rsaPrivateKey(key)
+		if err != nil {
+			return "", err
+		}
+
+		digest := sha256.Sum256([]byte(signingInput))
+		signature, err = rsa.SignPKCS1v15(rand.Reader, private, crypto.SHA256, digest[:])
+		if err != nil {
+			return "", fmt.Errorf("encountered error while signing jwt: %v", err)
+		}
+	default:
+		return "", fmt.Errorf("invalid jwt_algorithm provided: %v", algorithm)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// rsaPrivateKey parses a PEM-encoded PKCS#1 or PKCS#8 RSA private key.
+func rsaPrivateKey(raw string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(raw))
+	if block == nil {
+		return nil, fmt.Errorf("jwt_key must be a PEM-encoded RSA private key for RS256")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwt_key provided; %q", err)
+	}
+
+	private, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("jwt_key must be an RSA private key for RS256; got: %T", key)
+	}
+
+	return private, nil
+}
+
+// rsaPublicKey parses a PEM-encoded RSA public key or certificate.
+func rsaPublicKey(raw string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(raw))
+	if block == nil {
+		return nil, fmt.Errorf("jwt_verify_key must be a PEM-encoded RSA public key or certificate for RS256")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		cert, err = x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			key = cert.PublicKey
+		}
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwt_verify_key provided; %q", err)
+	}
+
+	public, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("jwt_verify_key must be an RSA public key for RS256; got: %T", key)
+	}
+
+	return public, nil
+}
+
+// verifyJWT checks token's signature with key and returns its claims. The
+// algorithm is fixed by the config rather than taken from the token, so a
+// token cannot downgrade itself to none or swap RS256 for HS256.
+func verifyJWT(token string, algorithm string, key string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("jwt must have three dot-separated parts; got: %d", len(parts))
+	}
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("jwt header is not valid base64url: %v", err)
+	}
+
+	header := struct {
+		Alg string `json:"alg"`
+	}{}
+	err = json.Unmarshal(rawHeader, &header)
+	if err != nil {
+		return nil, fmt.Errorf("jwt header is not valid json: %v", err)
+	}
+
+	if header.Alg != algorithm {
+		return nil, fmt.Errorf("expected jwt alg %s; got: %s", algorithm, header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("jwt signature is not valid base64url: %v", err)
+	}
+
+	signingInput := parts[0] + "." + parts[1]
+	switch algorithm {
+	case "HS256":
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return nil, fmt.Errorf("jwt signature does not verify")
+		}
+	case "RS256":
+		public, err := rsaPublicKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		digest := sha256.Sum256([]byte(signingInput))
+		err = rsa.VerifyPKCS1v15(public, crypto.SHA256, digest[:], signature)
+		if err != nil {
+			return nil, fmt.Errorf("jwt signature does not verify")
+		}
+	default:
+		return nil, fmt.Errorf("invalid jwt_algorithm provided: %v", algorithm)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("jwt payload is not valid base64url: %v", err)
+	}
+
+	doc, err := decodeJSON(payload)
+	if err != nil {
+		return nil, fmt.Errorf("jwt payload is not valid json: %v", err)
+	}
+
+	claims, ok := doc.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("jwt payload must be a JSON object; got: %s", jsonType(doc))
+	}
+
+	return claims, nil
+}
+
+// checkTimeClaims rejects a token that has expired or is not yet valid.
+func checkTimeClaims(claims map[string]any, now time.Time) error {
+	for _, name := range []string{"exp", "nbf"} {
+		value, ok := claims[name]
+		if !ok {
+			continue
+		}
+
+		number, ok := value.(json.Number)
+		if !ok {
+			return fmt.Errorf("jwt %s claim must be a number; got: %s", name, jsonType(value))
+		}
+
+		seconds, err := number.Float64()
+		if err != nil {
+			return fmt.Errorf("jwt %s claim must be a number; got: %v", name, number)
+		}
+
+		at := time.Unix(int64(seconds), 0)
+		if name == "exp" && !now.Before(at) {
+			return fmt.Errorf("jwt expired at %s", at.UTC().Format(time.RFC3339))
+		}
+		if name == "nbf" && now.Before(at) {
+			return fmt.Errorf("jwt is not valid before %s", at.UTC().Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+// validJWTMatcher extracts a JWT from the response, from a header, a cookie
+// or a JSON body path per jwt_source and jwt_field, verifies it against
+// jwt_verify_key and expects its claims to contain the JSON object in
+// expected_output, if any.
+type validJWTMatcher struct{}
+
+func (validJWTMatcher) ValidateConfig(conf Schema) error {
+	if conf.JWTVerifyKey == "" {
+		return fmt.Errorf("validJwt requires jwt_verify_key to be provided")
+	}
+
+	switch conf.JWTAlgorithm {
+	case "HS256":
+	case "RS256":
+		_, err := rsaPublicKey(conf.JWTVerifyKey)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid jwt_algorithm provided: %v", conf.JWTAlgorithm)
+	}
+
+	if conf.JWTSource != "header" && conf.JWTSource != "cookie" && conf.JWTSource != "json" {
+		return fmt.Errorf("invalid jwt_source provided: %v", conf.JWTSource)
+	}
+
+	if conf.JWTField == "" {
+		return fmt.Errorf("validJwt requires jwt_field to be provided")
+	}
+
+	if conf.JWTSource == "json" {
+		_, err := parsePath(conf.JWTField)
+		if err != nil {
+			return err
+		}
+	}
+
+	if conf.ExpectedOutput != "" {
+		expected, err := decodeJSON([]byte(conf.ExpectedOutput))
+		if err != nil {
+			return fmt.Errorf("expected_output is not valid json: %v", err)
+		}
+		if _, ok := expected.(map[string]any); !ok {
+			return fmt.Errorf("expected_output must be a JSON object of claims; got: %s", jsonType(expected))
+		}
+	}
+
+	return nil
+}
+
+func (validJWTMatcher) Match(ctx context.Context, ex *Exchange) error {
+	conf := ex.Config
+
+	token, err := responseJWT(ex)
+	if err != nil {
+		return err
+	}
+
+	claims, err := verifyJWT(token, conf.JWTAlgorithm, conf.JWTVerifyKey)
+	if err != nil {
+		return err
+	}
+
+	err = checkTimeClaims(claims, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if conf.ExpectedOutput != "" {
+		expected, err := decodeJSON([]byte(conf.ExpectedOutput))
+		if err != nil {
+			return fmt.Errorf("expected_output is not valid json: %v", err)
+		}
+
+		if !jsonSubsetOf(expected, claims) {
+			return fmt.Errorf("jwt claims do not contain expected output")
+		}
+	}
+
+	return nil
+}
+
+// responseJWT finds the token jwt_source and jwt_field point at.
+func responseJWT(ex *Exchange) (string, error) {
+	conf := ex.Config
+
+	switch conf.JWTSource {
+	case "header":
+		value := strings.TrimSpace(ex.Response.Header.Get(conf.JWTField))
+		if value == "" {
+			return "", fmt.Errorf("response has no %s header", conf.JWTField)
+		}
+		if scheme, token, ok := strings.Cut(value, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			value = strings.TrimSpace(token)
+		}
+		return value, nil
+	case "cookie":
+		for _, cookie := range ex.Response.Cookies() {
+			if cookie.Name == conf.JWTField {
+				return cookie.Value, nil
+			}
+		}
+		return "", fmt.Errorf("response sets no %s cookie", conf.JWTField)
+	default:
+		body, err := readBody(ex)
+		if err != nil {
+			return "", err
+		}
+
+		doc, err := decodeJSON(body)
+		if err != nil {
+			return "", fmt.Errorf("response body is not valid json: %v", err)
+		}
+
+		value, err := lookupPath(doc, conf.JWTField)
+		if err != nil {
+			return "", err
+		}
+
+		token, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("expected a string jwt at %s; got: %s", conf.JWTField, jsonType(value))
+		}
+		return token, nil
+	}
+}
@@ -0,0 +1,297 @@
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testRSAKeys returns a fresh RSA key pair as PEM.
+func testRSAKeys(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	private := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return string(private), string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public}))
+}
+
+func TestValidateJWT(t *testing.T) {
+	private, public := testRSAKeys(t)
+
+	tests := []struct {
+		name string
+		conf Schema
+		err  string
+	}{
+		{"hs256", Schema{JWTAlgorithm: "HS256", JWTKey: "secret", JWTTTLSeconds: 60, JWTClaims: `{"sub": "x"}`}, ""},
+		{"rs256", Schema{JWTAlgorithm: "RS256", JWTKey: private, JWTTTLSeconds: 60}, ""},
+		{"templated claims", Schema{JWTAlgorithm: "HS256", JWTKey: "secret", JWTTTLSeconds: 60, JWTClaims: `{"jti": "{{nonce}}"}`}, ""},
+		{"no key", Schema{JWTAlgorithm: "HS256", JWTTTLSeconds: 60}, "requires jwt_key"},
+		{"public key for rs256", Schema{JWTAlgorithm: "RS256", JWTKey: public, JWTTTLSeconds: 60}, "invalid jwt_key provided"},
+		{"not pem", Schema{JWTAlgorithm: "RS256", JWTKey: "secret", JWTTTLSeconds: 60}, "must be a PEM-encoded RSA private key"},
+		{"no ttl", Schema{JWTAlgorithm: "HS256", JWTKey: "secret"}, "jwt_ttl_seconds must be positive"},
+		{"claims array", Schema{JWTAlgorithm: "HS256", JWTKey: "secret", JWTTTLSeconds: 60, JWTClaims: `[1]`}, "jwt_claims must be a JSON object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkError(t, validateJWT(tt.conf), tt.err)
+		})
+	}
+}
+
+func TestMintJWTClaims(t *testing.T) {
+	token, err := mintJWT("HS256", "secret", map[string]any{"sub": "team01", "n": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := strings.Split(token, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := map[string]any{}
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims["sub"] != "team01" || claims["n"] != float64(1) {
+		t.Errorf("minted claims = %v", claims)
+	}
+}
+
+func TestAuthJWT(t *testing.T) {
+	// The server checks the HS256 signature itself and echoes the claims; a
+	// rejected token gets an empty 401.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		parts := strings.Split(token, ".")
+		if !ok || len(parts) != 3 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(parts[0] + "." + parts[1]))
+		if base64.RawURLEncoding.EncodeToString(mac.Sum(nil)) != parts[2] {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		w.Write(payload)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		key    string
+		claims string
+		want   string
+	}{
+		{"claims sent", "secret", `{"sub": "team01"}`, ""},
+		{"wrong key", "other", `{"sub": "team01"}`, "response body is not valid json"},
+		{"claims missing", "secret", `{"sub": "team02"}`, "response json does not contain expected output"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runAgainst(t, server, map[string]any{
+				"auth":            "jwt",
+				"jwt_key":         tt.key,
+				"jwt_claims":      tt.claims,
+				"match_type":      "jsonSubset",
+				"expected_output": `{"sub": "team01"}`,
+			})
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestAuthJWTTimes(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims string
+		iat    func(int64) bool
+		ttl    int64
+	}{
+		{"added", `{}`, func(iat int64) bool { return iat > 0 }, 90},
+		{"kept", `{"iat": 1, "exp": 91}`, func(iat int64) bool { return iat == 1 }, 90},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "http://example.com/", nil)
+			err := authJWT(context.Background(), Schema{JWTAlgorithm: "HS256", JWTKey: "secret", JWTTTLSeconds: 90, JWTClaims: tt.claims}, req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parts := strings.Split(strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "), ".")
+			payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+			claims := struct{ Iat, Exp int64 }{}
+			json.Unmarshal(payload, &claims)
+
+			if !tt.iat(claims.Iat) || claims.Exp-claims.Iat != tt.ttl {
+				t.Errorf("iat = %d, exp = %d; want exp %d seconds after iat", claims.Iat, claims.Exp, tt.ttl)
+			}
+		})
+	}
+}
+
+func TestVerifyJWT(t *testing.T) {
+	private, public := testRSAKeys(t)
+	_, otherPublic := testRSAKeys(t)
+	claims := map[string]any{"sub": "team01"}
+
+	hs256, err := mintJWT("HS256", "secret", claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs256, err := mintJWT("RS256", private, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + strings.Split(hs256, ".")[1] + "."
+	tampered := strings.Split(hs256, ".")[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin"}`)) + "." + strings.Split(hs256, ".")[2]
+
+	tests := []struct {
+		name      string
+		token     string
+		algorithm string
+		key       string
+		err       string
+	}{
+		{"hs256", hs256, "HS256", "secret", ""},
+		{"rs256", rs256, "RS256", public, ""},
+		{"hs256 wrong key", hs256, "HS256", "other", "jwt signature does not verify"},
+		{"rs256 wrong key", rs256, "RS256", otherPublic, "jwt signature does not verify"},
+		{"rs256 as hs256", rs256, "HS256", public, "expected jwt alg HS256; got: RS256"},
+		{"alg none", unsigned, "HS256", "secret", "expected jwt alg HS256; got: none"},
+		{"tampered payload", tampered, "HS256", "secret", "jwt signature does not verify"},
+		{"two parts", "a.b", "HS256", "secret", "three dot-separated parts; got: 2"},
+		{"bad header", "!!.b.c", "HS256", "secret", "jwt header is not valid base64url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifyJWT(tt.token, tt.algorithm, tt.key)
+			checkError(t, err, tt.err)
+			if tt.err == "" && got["sub"] != "team01" {
+				t.Errorf("verifyJWT() claims = %v; want sub team01", got)
+			}
+		})
+	}
+}
+
+func TestCheckTimeClaims(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name   string
+		claims string
+		err    string
+	}{
+		{"no claims", `{}`, ""},
+		{"valid", `{"exp": 1700000060, "nbf": 1699999940}`, ""},
+		{"expired", `{"exp": 1700000000}`, "jwt expired at 2023-11-14T22:13:20Z"},
+		{"not yet valid", `{"nbf": 1700000060}`, "jwt is not valid before"},
+		{"fractional exp", `{"exp": 1700000060.5}`, ""},
+		{"string exp", `{"exp": "tomorrow"}`, "jwt exp claim must be a number; got: string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := decodeJSON([]byte(tt.claims))
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkError(t, checkTimeClaims(doc.(map[string]any), now), tt.err)
+		})
+	}
+}
+
+func TestValidJWTMatcher(t *testing.T) {
+	token, err := mintJWT("HS256", "secret", map[string]any{"sub": "team01", "role": "admin", "exp": time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := mintJWT("HS256", "secret", map[string]any{"sub": "team01", "exp": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/header":
+			w.Header().Set("Authorization", "Bearer "+token)
+		case "/cookie":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: token})
+		case "/json":
+			w.Write([]byte(`{"data": {"token": "` + token + `"}}`))
+		case "/expired":
+			w.Write([]byte(`{"data": {"token": "` + expired + `"}}`))
+		case "/number":
+			w.Write([]byte(`{"data": {"token": 7}}`))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		conf map[string]any
+		want string
+	}{
+		{"header", map[string]any{"url": server.URL + "/header", "jwt_source": "header", "jwt_field": "Authorization"}, ""},
+		{"cookie", map[string]any{"url": server.URL + "/cookie", "jwt_source": "cookie", "jwt_field": "session"}, ""},
+		{"json", map[string]any{"url": server.URL + "/json"}, ""},
+		{"claims", map[string]any{"url": server.URL + "/json", "expected_output": `{"role": "admin"}`}, ""},
+		{"claims differ", map[string]any{"url": server.URL + "/json", "expected_output": `{"role": "user"}`}, "jwt claims do not contain expected output"},
+		{"wrong key", map[string]any{"url": server.URL + "/json", "jwt_verify_key": "other"}, "jwt signature does not verify"},
+		{"expired", map[string]any{"url": server.URL + "/expired"}, "jwt expired at 1970-01-01T00:00:01Z"},
+		{"no header", map[string]any{"url": server.URL + "/cookie", "jwt_source": "header", "jwt_field": "Authorization"}, "response has no Authorization header"},
+		{"no cookie", map[string]any{"url": server.URL + "/header", "jwt_source": "cookie", "jwt_field": "session"}, "response sets no session cookie"},
+		{"not a string", map[string]any{"url": server.URL + "/number"}, "expected a string jwt at data.token; got: number"},
+		{"no verify key", map[string]any{"url": server.URL + "/json", "jwt_verify_key": ""}, "validJwt requires jwt_verify_key"},
+		{"claims not object", map[string]any{"url": server.URL + "/json", "expected_output": `["admin"]`}, "expected_output must be a JSON object of claims; got: array"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := map[string]any{
+				"match_type":     "validJwt",
+				"jwt_verify_key": "secret",
+				"jwt_field":      "data.token",
+			}
+			for key, value := range tt.conf {
+				conf[key] = value
+			}
+
+			err := runAgainst(t, server, conf)
+			checkError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptrace"
+)
+
+// keepAliveMatcher sends the request a second time after fully consuming the
+// first response and expects the transport to reuse the same connection.
+type keepAliveMatcher struct{}
+
+func (keepAliveMatcher) ValidateConfig(conf Schema) error {
+	if conf.MaxTLSHandshakeMs > 0 {
+		return fmt.Errorf("keepAlive cannot be combined with max_tls_handshake_ms, which disables connection reuse")
+	}
+
+	return nil
+}
+
+func (keepAliveMatcher) Match(ctx context.Context, ex *Exchange) error {
+	// The connection only returns to the idle pool once the first body has
+	// been read to EOF and closed.
+	_, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+	ex.Response.Body.Close()
+
+	if ex.Response.Close {
+		return fmt.Errorf("server asked to close the connection after the first response")
+	}
+
+	req, err := ex.NewRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	reused := false
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			reused = info.Reused
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	resp, err := ex.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("encounted error while making second request: %v", err.Error())
+	}
+	defer drainBody(resp.Body)
+
+	if !reused {
+		return fmt.Errorf("second request opened a new connection; keep-alive is not working")
+	}
+
+	return nil
+}
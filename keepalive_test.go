@@ -0,0 +1,104 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestKeepAlive(t *testing.T) {
+	tests := []struct {
+		name  string
+		close bool
+		conf  map[string]any
+		want  string
+	}{
+		{"reused", false, nil, ""},
+		{"server closes", true, nil, "server asked to close the connection"},
+		{"handshake limit", false, map[string]any{"max_tls_handshake_ms": 500}, "cannot be combined with max_tls_handshake_ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.close {
+					w.Header().Set("Connection", "close")
+				}
+				w.Write([]byte("ok"))
+			}))
+			defer server.Close()
+
+			doc := map[string]any{"url": server.URL, "match_type": "keepAlive"}
+			for key, value := range tt.conf {
+				doc[key] = value
+			}
+			config, _ := json.Marshal(doc)
+
+			err := Validate(string(config))
+			if err == nil {
+				err = New().Run(context.Background(), string(config))
+			}
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Run() = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Run() = %v; want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeepAliveConnections(t *testing.T) {
+	tests := []struct {
+		name  string
+		drop  bool
+		conns int32
+		want  string
+	}{
+		{"one connection", false, 1, ""},
+		// The server hangs up without saying so; only the second request
+		// finds out, on a fresh connection.
+		{"silently dropped", true, 2, "second request opened a new connection"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conns atomic.Int32
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !tt.drop {
+					w.Write([]byte("ok"))
+					return
+				}
+				conn, buf, err := http.NewResponseController(w).Hijack()
+				if err != nil {
+					return
+				}
+				buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok")
+				buf.Flush()
+				conn.Close()
+			}))
+			server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					conns.Add(1)
+				}
+			}
+			server.Start()
+			defer server.Close()
+
+			err := runAgainst(t, server, map[string]any{"match_type": "keepAlive"})
+			checkError(t, err, tt.want)
+
+			if got := conns.Load(); got != tt.conns {
+				t.Errorf("server saw %d connections; want %d", got, tt.conns)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// sample is the outcome of one request within a multi-request mode.
+type sample struct {
+	latency time.Duration
+	err     error
+}
+
+func validateLoad(conf Schema) error {
+	if conf.Samples < 1 {
+		return fmt.Errorf("samples must be at least 1; got: %d", conf.Samples)
+	}
+
+	if conf.Concurrency < 1 || conf.Concurrency > conf.Samples {
+		return fmt.Errorf("concurrency must be between 1 and samples; got: %d", conf.Concurrency)
+	}
+
+	if conf.MaxP50Ms < 0 || conf.MaxP95Ms < 0 {
+		return fmt.Errorf("latency thresholds must not be negative; got: p50 %d, p95 %d", conf.MaxP50Ms, conf.MaxP95Ms)
+	}
+
+	if conf.MinSuccessRate < 0 || conf.MinSuccessRate > 100 {
+		return fmt.Errorf("min_success_rate must be between 0 and 100; got: %d", conf.MinSuccessRate)
+	}
+
+	return nil
+}
+
+// collect runs n attempts with at most concurrency in flight and returns
+// their outcomes in completion order.
+func (c *Checker) collect(ctx context.Context, conf Schema, res *Result, n int, concurrency int) []sample {
+	samples := make([]sample, 0, n)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+
+	for i := 0; i < n; i++ {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			for len(samples) < n {
+				samples = append(samples, sample{err: ctx.Err()})
+			}
+			return samples
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			start := time.Now()
+			err := c.attempt(ctx, conf, res)
+			latency := time.Since(start)
+
+			mu.Lock()
+			samples = append(samples, sample{latency: latency, err: err})
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return samples
+}
+
+func (c *Checker) runLoad(ctx context.Context, conf Schema, res *Result) error {
+	samples := c.collect(ctx, conf, res, conf.Samples, conf.Concurrency)
+	recordAggregate(res, samples)
+
+	latencies := []time.Duration{}
+	var lastErr error
+	for _, s := range samples {
+		if s.err != nil {
+			lastErr = s.err
+			continue
+		}
+		latencies = append(latencies, s.latency)
+	}
+
+	rate := len(latencies) * 100 / len(samples)
+	if rate < conf.MinSuccessRate {
+		return fmt.Errorf("success rate %d%% below required %d%%; last error: %v", rate, conf.MinSuccessRate, lastErr)
+	}
+
+	if len(latencies) == 0 {
+		return nil
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	if p50 := percentile(latencies, 50); conf.MaxP50Ms > 0 && p50 > time.Duration(conf.MaxP50Ms)*time.Millisecond {
+		return fmt.Errorf("p50 latency %v exceeds %dms", p50.Round(time.Millisecond), conf.MaxP50Ms)
+	}
+
+	if p95 := percentile(latencies, 95); conf.MaxP95Ms > 0 && p95 > time.Duration(conf.MaxP95Ms)*time.Millisecond {
+		return fmt.Errorf("p95 latency %v exceeds %dms", p95.Round(time.Millisecond), conf.MaxP95Ms)
+	}
+
+	return nil
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+func validateBurst(conf Schema) error {
+	if conf.Samples < 1 {
+		return fmt.Errorf("samples must be at least 1; got: %d", conf.Samples)
+	}
+
+	if conf.MinSuccesses < 1 || conf.MinSuccesses > conf.Samples {
+		return fmt.Errorf("min_successes must be between 1 and samples; got: %d", conf.MinSuccesses)
+	}
+
+	return nil
+}
+
+// runBurst sends every sample at once and passes when at least MinSuccesses
+// of them pass.
+func (c *Checker) runBurst(ctx context.Context, conf Schema, res *Result) error {
+	samples := c.collect(ctx, conf, res, conf.Samples, conf.Samples)
+
+	passed := 0
+	var lastErr error
+	for _, s := range samples {
+		if s.err != nil {
+			lastErr = s.err
+			continue
+		}
+		passed++
+	}
+
+	if passed < conf.MinSuccesses {
+		return fmt.Errorf("%d of %d burst requests passed; need %d; last error: %v", passed, len(samples), conf.MinSuccesses, lastErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	ms := func(values ...int) []time.Duration {
+		durations := []time.Duration{}
+		for _, v := range values {
+			durations = append(durations, time.Duration(v)*time.Millisecond)
+		}
+		return durations
+	}
+
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		p      int
+		want   time.Duration
+	}{
+		{"single sample", ms(7), 95, 7 * time.Millisecond},
+		{"median of ten", ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 50, 5 * time.Millisecond},
+		{"p95 of ten is the slowest", ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 95, 10 * time.Millisecond},
+		{"p95 of twenty skips the slowest", ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20), 95, 19 * time.Millisecond},
+		{"p0 is the fastest", ms(3, 4), 0, 3 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.sorted, tt.p); got != tt.want {
+				t.Errorf("percentile(%v, %d) = %v; want %v", tt.sorted, tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSuccessRate(t *testing.T) {
+	// Every fourth request fails.
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1)%4 == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		rate int
+		want string
+	}{
+		{"rate met", 75, ""},
+		{"rate missed", 80, "success rate 75% below required 80%; last error: expected status code: 200; got: 503"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			err := runAgainst(t, server, map[string]any{
+				"mode":             "load",
+				"expected_output":  "200",
+				"samples":          8,
+				"concurrency":      1,
+				"min_success_rate": tt.rate,
+			})
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestLoadConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	err := runAgainst(t, server, map[string]any{
+		"mode":            "load",
+		"expected_output": "200",
+		"samples":         9,
+		"concurrency":     3,
+	})
+	checkError(t, err, "")
+
+	if got := peak.Load(); got != 3 {
+		t.Errorf("peak of %d requests in flight; want 3", got)
+	}
+}
+
+func TestLoadLatency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(30 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		conf map[string]any
+		want string
+	}{
+		{"within thresholds", map[string]any{"max_p50_ms": 5000, "max_p95_ms": 5000}, ""},
+		{"slow median", map[string]any{"max_p50_ms": 5}, "p50 latency"},
+		{"slow tail", map[string]any{"max_p95_ms": 5}, "p95 latency"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := map[string]any{"mode": "load", "expected_output": "200", "samples": 2, "concurrency": 2}
+			for key, value := range tt.conf {
+				conf[key] = value
+			}
+
+			err := runAgainst(t, server, conf)
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestBurst(t *testing.T) {
+	// Requests are held until all five have arrived, so the check only
+	// completes if the burst really is sent at once. The first two fail.
+	const samples = 5
+	var mu sync.Mutex
+	arrived := 0
+	all := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrived++
+		n := arrived
+		done := all
+		if n == samples {
+			close(done)
+		}
+		mu.Unlock()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+		}
+		if n <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		successes int
+		want      string
+	}{
+		{"enough passed", 3, ""},
+		{"too few passed", 4, "3 of 5 burst requests passed; need 4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			arrived = 0
+			all = make(chan struct{})
+			mu.Unlock()
+
+			start := time.Now()
+			err := runAgainst(t, server, map[string]any{
+				"mode":            "burst",
+				"expected_output": "200",
+				"samples":         samples,
+				"min_successes":   tt.successes,
+			})
+			checkError(t, err, tt.want)
+
+			if elapsed := time.Since(start); elapsed > 4*time.Second {
+				t.Errorf("burst took %v; its requests were not sent at once", elapsed)
+			}
+		})
+	}
+}
+
+func TestValidateLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		conf Schema
+		want string
+	}{
+		{"valid", Schema{Samples: 4, Concurrency: 4, MinSuccessRate: 100}, ""},
+		{"no samples", Schema{Samples: 0, Concurrency: 1}, "samples must be at least 1"},
+		{"concurrency above samples", Schema{Samples: 2, Concurrency: 3}, "concurrency must be between 1 and samples"},
+		{"negative threshold", Schema{Samples: 1, Concurrency: 1, MaxP95Ms: -1}, "latency thresholds must not be negative"},
+		{"rate over 100", Schema{Samples: 1, Concurrency: 1, MinSuccessRate: 101}, "min_success_rate must be between 0 and 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkError(t, validateLoad(tt.conf), tt.want)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// loginRequest turns a login config into a form post of auth_username and
+// auth_password that keeps the session cookies it is given.
+func loginRequest(conf Schema) Schema {
+	form := url.Values{}
+	form.Set(conf.UsernameField, conf.AuthUsername)
+	form.Set(conf.PasswordField, conf.AuthPassword)
+
+	conf.Verb = http.MethodPost
+	conf.Body = form.Encode()
+	conf.ContentType = "application/x-www-form-urlencoded"
+	conf.CookieJar = true
+
+	return conf
+}
+
+// loginMatcher expects the login form post to succeed: the page it lands on
+// contains expected_output, when set, and protected_url, when set, loads
+// with the session instead of bouncing back to the login page.
+type loginMatcher struct{}
+
+func (loginMatcher) ValidateConfig(conf Schema) error {
+	if conf.UsernameField == "" || conf.PasswordField == "" {
+		return fmt.Errorf("username_field and password_field must be provided")
+	}
+
+	if conf.ExpectedOutput == "" && conf.ProtectedURL == "" {
+		return fmt.Errorf("login requires expected_output or protected_url to recognize a successful login")
+	}
+
+	return nil
+}
+
+func (loginMatcher) Match(ctx context.Context, ex *Exchange) error {
+	return verifyLogin(ctx, ex)
+}
+
+func verifyLogin(ctx context.Context, ex *Exchange) error {
+	if ex.Response.StatusCode >= 400 {
+		return fmt.Errorf("login: expected a successful status code; got: %d", ex.Response.StatusCode)
+	}
+
+	if ex.Config.ExpectedOutput != "" {
+		body, err := readBody(ex)
+		if err != nil {
+			return err
+		}
+
+		if !strings.Contains(string(body), ex.Config.ExpectedOutput) {
+			return fmt.Errorf("login: expected output not found in response body")
+		}
+	}
+
+	if ex.Config.ProtectedURL == "" {
+		return nil
+	}
+
+	status, landed, err := visitProtected(ctx, ex, ex.Client)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("protected url: expected status code: %d; got: %d", http.StatusOK, status)
+	}
+	if landed.Path == ex.Request.URL.Path && landed.Host == ex.Request.URL.Host {
+		return fmt.Errorf("protected url: redirected back to the login page")
+	}
+
+	return nil
+}
+
+// visitProtected GETs protected_url through client and returns the status
+// and URL it ended up at.
+func visitProtected(ctx context.Context, ex *Exchange, client *http.Client) (int, *url.URL, error) {
+	req, err := ex.followUp(ctx, http.MethodGet, ex.Config.ProtectedURL)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("protected url: encounted error while making request: %v", err.Error())
+	}
+	drainBody(resp.Body)
+
+	return resp.StatusCode, resp.Request.URL, nil
+}
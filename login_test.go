@@ -0,0 +1,99 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// sessionServer is a login form at /login that opens /home with a session
+// cookie. Logging out at /logout clears the cookie and, when invalidate is
+// set, also ends the session on the server. With noCookie set, login sets no
+// cookie and /home is open to everyone.
+type sessionServer struct {
+	invalidate bool
+	noCookie   bool
+
+	mu       sync.Mutex
+	sessions map[string]bool
+	next     int
+}
+
+func (s *sessionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cookie, _ := r.Cookie("session")
+	loggedIn := s.noCookie || cookie != nil && s.sessions[cookie.Value]
+
+	switch r.URL.Path {
+	case "/login":
+		if r.Method != http.MethodPost {
+			w.Write([]byte("Please log in"))
+			return
+		}
+		if r.PostFormValue("user") != "admin" || r.PostFormValue("pass") != "hunter2" {
+			w.Write([]byte("Invalid login"))
+			return
+		}
+		if !s.noCookie {
+			s.next++
+			token := string(rune('a' + s.next))
+			s.sessions[token] = true
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: token, Path: "/"})
+		}
+		http.Redirect(w, r, "/home", http.StatusSeeOther)
+	case "/home":
+		if !loggedIn {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+		w.Write([]byte("Welcome back"))
+	case "/logout":
+		if s.invalidate && cookie != nil {
+			delete(s.sessions, cookie.Value)
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Path: "/", MaxAge: -1})
+		w.Write([]byte("Bye"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	tests := []struct {
+		name string
+		conf map[string]any
+		want string
+	}{
+		{"lands on welcome", map[string]any{"expected_output": "Welcome back"}, ""},
+		{"protected page", map[string]any{"protected_url": "/home"}, ""},
+		{"wrong password", map[string]any{"auth_password": "nope", "expected_output": "Welcome back"}, "login: expected output not found in response body"},
+		{"wrong password, protected page", map[string]any{"auth_password": "nope", "protected_url": "/home"}, "protected url: redirected back to the login page"},
+		{"missing protected page", map[string]any{"protected_url": "/admin"}, "protected url: expected status code: 200; got: 404"},
+		{"no way to tell", nil, "login requires expected_output or protected_url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(&sessionServer{sessions: map[string]bool{}})
+			defer server.Close()
+
+			conf := map[string]any{
+				"url":            server.URL + "/login",
+				"match_type":     "login",
+				"auth_username":  "admin",
+				"auth_password":  "hunter2",
+				"username_field": "user",
+				"password_field": "pass",
+			}
+			for key, value := range tt.conf {
+				conf[key] = value
+			}
+
+			err := runAgainst(t, server, conf)
+			checkError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// logoutMatcher logs in as login does, logs out at logout_url, and then
+// expects the pre-logout session cookies to no longer open protected_url.
+type logoutMatcher struct{}
+
+func (logoutMatcher) ValidateConfig(conf Schema) error {
+	err := loginMatcher{}.ValidateConfig(conf)
+	if err != nil {
+		return err
+	}
+
+	if conf.ProtectedURL == "" || conf.LogoutURL == "" {
+		return fmt.Errorf("logoutInvalidates requires protected_url and logout_url to be provided")
+	}
+
+	if conf.LogoutVerb != http.MethodGet && conf.LogoutVerb != http.MethodPost {
+		return fmt.Errorf("invalid logout_verb provided: %v", conf.LogoutVerb)
+	}
+
+	return nil
+}
+
+func (logoutMatcher) Match(ctx context.Context, ex *Exchange) error {
+	err := verifyLogin(ctx, ex)
+	if err != nil {
+		return err
+	}
+
+	protected, err := ex.Request.URL.Parse(ex.Config.ProtectedURL)
+	if err != nil {
+		return fmt.Errorf("invalid protected_url provided: %v; %q", ex.Config.ProtectedURL, err)
+	}
+	session := ex.Client.Jar.Cookies(protected)
+	if len(session) == 0 {
+		return fmt.Errorf("login set no session cookies for %s", protected)
+	}
+
+	req, err := ex.followUp(ctx, ex.Config.LogoutVerb, ex.Config.LogoutURL)
+	if err != nil {
+		return err
+	}
+
+	resp, err := ex.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("logout: encounted error while making request: %v", err.Error())
+	}
+	drainBody(resp.Body)
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("logout: expected a successful status code; got: %d", resp.StatusCode)
+	}
+
+	// Replay the old cookies by hand; the logout response may have cleared
+	// them from the jar.
+	replay := *ex.Client
+	replay.Jar = nil
+
+	req, err = ex.followUp(ctx, http.MethodGet, protected.String())
+	if err != nil {
+		return err
+	}
+	for _, cookie := range session {
+		req.AddCookie(cookie)
+	}
+
+	resp, err = replay.Do(req)
+	if err != nil {
+		return fmt.Errorf("protected url after logout: encounted error while making request: %v", err.Error())
+	}
+	drainBody(resp.Body)
+
+	if resp.StatusCode == http.StatusOK && resp.Request.URL.String() == protected.String() {
+		return fmt.Errorf("session cookies still open %s after logout", protected)
+	}
+
+	return nil
+}
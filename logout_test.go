@@ -0,0 +1,47 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLogoutInvalidates(t *testing.T) {
+	tests := []struct {
+		name   string
+		server *sessionServer
+		conf   map[string]any
+		want   string
+	}{
+		{"session ended", &sessionServer{invalidate: true}, nil, ""},
+		{"cookie cleared only", &sessionServer{}, nil, "session cookies still open"},
+		{"no session cookie", &sessionServer{invalidate: true, noCookie: true}, nil, "login set no session cookies"},
+		{"logout fails", &sessionServer{invalidate: true}, map[string]any{"logout_url": "/signout"}, "logout: expected a successful status code; got: 404"},
+		{"wrong password", &sessionServer{invalidate: true}, map[string]any{"auth_password": "nope"}, "protected url: redirected back to the login page"},
+		{"missing logout url", &sessionServer{}, map[string]any{"logout_url": ""}, "requires protected_url and logout_url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.server.sessions = map[string]bool{}
+			server := httptest.NewServer(tt.server)
+			defer server.Close()
+
+			conf := map[string]any{
+				"url":            server.URL + "/login",
+				"match_type":     "logoutInvalidates",
+				"auth_username":  "admin",
+				"auth_password":  "hunter2",
+				"username_field": "user",
+				"password_field": "pass",
+				"protected_url":  "/home",
+				"logout_url":     "/logout",
+			}
+			for key, value := range tt.conf {
+				conf[key] = value
+			}
+
+			err := runAgainst(t, server, conf)
+			checkError(t, err, tt.want)
+		})
+	}
+}
@@ -3,62 +3,221 @@ package http
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
+	"net"
 	"net/http"
+	"net/url"
 	"regexp"
 	"slices"
-	"strconv"
 	"strings"
-
-	"github.com/scorify/schema"
+	"time"
 )
 
 type Schema struct {
-	URL            string `key:"url"`
-	Verb           string `key:"verb" default:"GET" enum:"GET,POST,PUT,DELETE,PATCH,HEAD,OPTIONS,CONNECT,TRACE"`
-	ExpectedOutput string `key:"expected_output"`
-	MatchType      string `key:"match_type" default:"statusCode" enum:"statusCode,substringMatch,exactMatch,regexMatch"`
-	Insecure       bool   `key:"insecure"`
-	Headers        string `key:"headers"`
-	Body           string `key:"body"`
-	ContentType    string `key:"content_type" default:"empty" enum:"plain/text,application/json,x-www-form-urlencoded,empty"`
+	URL               string `key:"url" description:"URL to request, or several one per line; may contain {{.name}} placeholders or be relative, filled from the target the engine attaches with WithTarget; time and nonce helpers such as {{timestamp}} and {{nonce}} are also available here and in body and headers"`
+	Verb              string `key:"verb" default:"GET" enum:"GET,POST,PUT,DELETE,PATCH,HEAD,OPTIONS,CONNECT,TRACE" description:"HTTP method to send"`
+	ExpectedOutput    string `key:"expected_output" description:"Value the response is checked against; its meaning depends on match_type"`
+	MatchType         string `key:"match_type" default:"statusCode" enum:"statusCode,substringMatch,exactMatch,regexMatch,notModified,partialContent,headConsistent,corsPreflight,compressed,keepAlive,methodBlocked,traceDisabled,validJson,validXml,htmlElement,crawl,sitemap,ocspStapled,hsts,cookieSecurity,serverBanner,allSubstrings,anySubstring,jsonEquals,jsonSubset,yamlPath,csvValue,binaryMatch,image,pdf,subresourceIntegrity,healthcheck,prometheus,crudRoundTrip,uploadIntegrity,login,logoutInvalidates,rejectsBadCredentials,minVersion,vhostSweep,sniMismatch,cacheControl,etag,validJwt,pagination,followLink,webhook" description:"How the response is compared with expected_output"`
+	Insecure          bool   `key:"insecure" description:"Accept any TLS certificate; verification problems are still reported in the result notes"`
+	Headers           string `key:"headers" description:"Request headers as header:value;header:value"`
+	RequestHeaders    string `key:"request_headers" description:"Request headers, one \"Header: value\" per line"`
+	Cookies           string `key:"cookies" description:"Request cookies as name=value; name=value"`
+	Range             string `key:"range" description:"Range header to send, e.g. bytes=0-1023"`
+	Body              string `key:"body" description:"Request body; requires a non-empty content_type"`
+	ContentType       string `key:"content_type" default:"empty" enum:"plain/text,application/json,x-www-form-urlencoded,empty" description:"Content-Type of the request body"`
+	Auth              string `key:"auth" default:"none" description:"Authentication scheme applied to the request"`
+	AuthUsername      string `key:"auth_username" description:"Username for basic auth"`
+	AuthPassword      string `key:"auth_password" description:"Password for basic auth"`
+	AuthToken         string `key:"auth_token" description:"Token for bearer auth"`
+	Mode              string `key:"mode" default:"single" enum:"single,load,burst,rateLimit,consecutive,sample" description:"How many requests make up one check"`
+	Samples           int    `key:"samples" default:"10" description:"Number of requests sent in load, burst, rateLimit and sample modes"`
+	Concurrency       int    `key:"concurrency" default:"1" description:"Maximum requests in flight at once in load mode"`
+	MaxP50Ms          int    `key:"max_p50_ms" description:"Fail load mode if median latency exceeds this many milliseconds; 0 disables"`
+	MaxP95Ms          int    `key:"max_p95_ms" description:"Fail load mode if 95th percentile latency exceeds this many milliseconds; 0 disables"`
+	MinSuccessRate    int    `key:"min_success_rate" default:"100" description:"Percentage of load and sample mode requests that must pass"`
+	MinSuccesses      int    `key:"min_successes" default:"1" description:"Number of burst mode requests that must pass"`
+	ExpectLimited     bool   `key:"expect_rate_limited" default:"true" description:"In rateLimit mode, require a 429 response; when false, require none"`
+	RequireRetry      bool   `key:"require_retry_after" default:"true" description:"In rateLimit mode, require 429 responses to carry a valid Retry-After header"`
+	CORSOrigin        string `key:"cors_origin" description:"Origin sent with the corsPreflight request"`
+	CORSMethod        string `key:"cors_method" default:"GET" description:"Access-Control-Request-Method sent with the corsPreflight request"`
+	CORSHeaders       string `key:"cors_headers" description:"Comma-separated Access-Control-Request-Headers sent with the corsPreflight request"`
+	CORSCreds         bool   `key:"cors_credentials" description:"Require Access-Control-Allow-Credentials: true on the preflight response"`
+	ForbiddenVerb     string `key:"forbidden_verb" default:"PUT" description:"Method that methodBlocked expects the server to refuse"`
+	RejectStatuses    string `key:"reject_statuses" description:"Comma-separated status codes that count as a refusal; empty uses the match type's defaults"`
+	ReadIdleTimeoutMs int    `key:"read_idle_timeout_ms" description:"Fail if the response body stalls for this many milliseconds between reads; 0 disables"`
+	ArtifactDir       string `key:"artifact_dir" description:"Directory to write each response to for later audit; empty disables"`
+	ArtifactLabels    string `key:"artifact_labels" description:"Comma-separated key=value labels recorded with each artifact, e.g. team=blue"`
+	ChangeDetection   string `key:"change_detection" default:"off" enum:"off,flag,fail" description:"Compare the body hash across runs: flag notes a change, fail fails until the original content returns"`
+	StateFile         string `key:"state_file" description:"JSON file holding change_detection state; empty keeps it in memory"`
+	JSONType          string `key:"json_type" default:"any" enum:"any,object,array,string,number,boolean,null" description:"Top-level JSON type required by validJson"`
+	CrawlLimit        int    `key:"crawl_limit" default:"20" description:"Maximum number of same-origin links fetched by crawl"`
+	CrawlMaxErrors    int    `key:"crawl_max_errors" description:"Number of crawled links allowed to fail before crawl fails"`
+	SitemapSamples    int    `key:"sitemap_samples" description:"Number of randomly chosen sitemap URLs that must return 200"`
+	ExpectALPN        string `key:"expect_alpn" description:"Protocol the TLS handshake must negotiate via ALPN: h2 or http/1.1; setting it offers HTTP/2"`
+	MaxTLSHandshakeMs int    `key:"max_tls_handshake_ms" description:"Fail if the TLS handshake takes longer than this many milliseconds; setting it disables connection reuse so every check handshakes; 0 disables"`
+	FinalURLPattern   string `key:"final_url_pattern" description:"Regex the URL reached after following redirects must match"`
+	ForbidDowngrade   bool   `key:"forbid_downgrade" description:"Fail if any redirect moves from https to http"`
+	HSTSMinMaxAge     int    `key:"hsts_min_max_age" description:"Minimum Strict-Transport-Security max-age in seconds required by hsts"`
+	HSTSSubdomains    bool   `key:"hsts_include_subdomains" description:"Require the includeSubDomains directive in hsts"`
+	HSTSPreload       bool   `key:"hsts_preload" description:"Require the preload directive in hsts"`
+	CookieNames       string `key:"cookie_names" description:"Comma-separated cookies cookieSecurity inspects; empty inspects every cookie set"`
+	CookieSecure      bool   `key:"cookie_secure" default:"true" description:"Require the Secure attribute in cookieSecurity"`
+	CookieHTTPOnly    bool   `key:"cookie_httponly" default:"true" description:"Require the HttpOnly attribute in cookieSecurity"`
+	CookieSameSite    string `key:"cookie_samesite" default:"any" enum:"any,Strict,Lax,None" description:"SameSite value required by cookieSecurity"`
+	BannerPolicy      string `key:"banner_policy" default:"hidden" enum:"hidden,match" description:"serverBanner policy: hidden requires no Server header or one without version details; match requires it to match the expected_output regex"`
+	ExpectContentType string `key:"expect_content_type" description:"Media type the response Content-Type must have, e.g. application/json, or an anchored regex such as text/.*"`
+	ForbiddenStrings  string `key:"forbidden_strings" description:"Strings, one per line, that fail the check if any appears in the response body"`
+	NormalizeNewlines bool   `key:"normalize_newlines" description:"Convert CRLF to LF in body and expected_output before exactMatch"`
+	TrimWhitespace    bool   `key:"trim_whitespace" description:"Trim leading and trailing whitespace from body and expected_output before exactMatch"`
+	ValuePath         string `key:"value_path" description:"Path into a structured response, e.g. spec.containers[0].image"`
+	CSVColumn         string `key:"csv_column" description:"Column csvValue inspects, by header name or zero-based index"`
+	CSVRow            int    `key:"csv_row" default:"-1" description:"Zero-based data row csvValue compares; -1 passes if any row in the column has the value"`
+	CSVHeader         bool   `key:"csv_header" default:"true" description:"Treat the first CSV record as a header row"`
+	ImageFormat       string `key:"image_format" default:"any" enum:"any,png,jpeg,gif" description:"Image format required by image"`
+	ImageMinWidth     int    `key:"image_min_width" description:"Minimum image width in pixels for image"`
+	ImageMinHeight    int    `key:"image_min_height" description:"Minimum image height in pixels for image"`
+	MaxMetaRefresh    int    `key:"max_meta_refresh" description:"Follow up to this many HTML meta refresh redirects before matching; 0 disables"`
+	AssetSamples      int    `key:"asset_samples" description:"After the check passes, fetch this many random stylesheets, scripts and images from an HTML response; each must return 200; 0 disables"`
+	URLPolicy         string `key:"url_policy" default:"all" enum:"all,any,round_robin" description:"How a url listing several URLs, one per line, passes: all must pass, any one must pass, or round_robin checks the next URL in turn on each run"`
+	FallbackURL       string `key:"fallback_url" description:"URL tried instead when no response at all is received from url, e.g. a node behind a load balancer; checks that get a failing response or a rejected TLS handshake do not fall back"`
+	StatusScores      string `key:"status_scores" description:"Partial credit reported in the result score per response status, as status=fraction pairs like 200=1,503=0.25; unlisted statuses score 0"`
+	Retries           int    `key:"retries" description:"In single mode, retry up to this many times when no response is received or its status is in retry_on_status; rejected TLS handshakes and other failures are never retried"`
+	RetryOnStatus     string `key:"retry_on_status" default:"502,503,504" description:"Comma-separated response statuses that are retried"`
+	RetryDelayMs      int    `key:"retry_delay_ms" default:"500" description:"Milliseconds to wait between retries"`
+	JitterMs          int    `key:"jitter_ms" description:"Wait a random 0 to this many milliseconds before sending, so checks started together do not all arrive at once"`
+	Consecutive       int    `key:"consecutive_successes" default:"3" description:"Attempts in a row that must pass in consecutive mode"`
+	AttemptDelayMs    int    `key:"attempt_delay_ms" default:"1000" description:"Milliseconds between attempts in consecutive and sample modes"`
+	HealthPath        string `key:"health_path" default:"/healthz" description:"Path healthcheck requests when url has none"`
+	HealthField       string `key:"health_field" default:"status" description:"JSON path healthcheck reads; it must equal expected_output, or ok, healthy, up or pass when that is empty"`
+	ResourceIDPath    string `key:"resource_id_path" default:"id" description:"JSON path of the created resource's id, used by crudRoundTrip when the response has no Location header"`
+	UploadSize        int    `key:"upload_size" default:"1024" description:"Bytes of random payload uploadIntegrity sends in place of body"`
+	DownloadURL       string `key:"download_url" description:"URL uploadIntegrity downloads the payload from; defaults to the upload's Location header, then url"`
+	CookieJar         bool   `key:"cookie_jar" description:"Keep cookies the server sets during a check and send them on redirects and follow-up requests"`
+	UsernameField     string `key:"username_field" default:"username" description:"Form field login posts auth_username as"`
+	PasswordField     string `key:"password_field" default:"password" description:"Form field login posts auth_password as"`
+	ProtectedURL      string `key:"protected_url" description:"Page login must be able to load with the session it got"`
+	LogoutURL         string `key:"logout_url" description:"URL logoutInvalidates requests to end the session"`
+	LogoutVerb        string `key:"logout_verb" default:"GET" enum:"GET,POST" description:"HTTP method logoutInvalidates sends to logout_url"`
+	VersionPattern    string `key:"version_pattern" description:"Regex minVersion uses to find the version; its first capture group is used if it has one; defaults to the first dotted version number"`
+	VersionHeader     string `key:"version_header" description:"Response header minVersion reads the version from, such as Server, instead of the body"`
+	SNIName           string `key:"sni_name" description:"TLS server name sniMismatch sends while the Host header names the url host"`
+	MismatchPolicy    string `key:"mismatch_policy" default:"reject" enum:"reject,default" description:"What sniMismatch expects: reject requires a failed handshake or 4xx status; default requires the request to be served"`
+	TunnelURL         string `key:"tunnel_url" description:"With verb CONNECT, url is a proxy under test and this URL is requested with tunnel_verb through a CONNECT tunnel it opens; the match applies to the tunneled response"`
+	ForwardedFor      string `key:"forwarded_for" description:"X-Forwarded-For value to send: comma-separated client addresses, nearest last"`
+	RealIP            string `key:"real_ip" description:"X-Real-IP address to send"`
+	Forwarded         string `key:"forwarded" description:"RFC 7239 Forwarded header value to send, e.g. for=192.0.2.60;proto=https"`
+	RequestIDHeader   string `key:"request_id_header" description:"Send a fresh random ID in this header, e.g. X-Request-ID, and require it to be echoed in a response header or the body"`
+	IdempotencyKey    bool   `key:"idempotency_key" description:"Send a fresh random Idempotency-Key header"`
+	IdempotencyReplay bool   `key:"idempotency_replay" description:"Repeat the request with the same Idempotency-Key and require the same status and Location, or body, back"`
+	ExpectEarlyHints  bool   `key:"expect_early_hints" description:"Require a 103 Early Hints response before the final response"`
+	EarlyHintsLinks   string `key:"early_hints_links" description:"Values the 103 Early Hints Link headers must mention, one per line, e.g. </style.css>"`
+	ETagPolicy        string `key:"etag_policy" default:"stable" enum:"stable,changes" description:"Whether etag expects the ETag to stay the same or change between two requests"`
+	ExpectVary        string `key:"expect_vary" description:"Comma-separated headers the response's Vary header must include, e.g. Accept-Encoding, Origin"`
+	MaxRedirects      int    `key:"max_redirects" default:"10" description:"Redirects followed before the last redirect response is checked instead; a redirect back to an earlier URL fails at once as a loop"`
+	Accept            string `key:"accept" description:"Accept header to send, e.g. application/json or application/xml"`
+	AcceptNegotiated  bool   `key:"accept_negotiated" description:"Require the response Content-Type to be one that accept asked for"`
+	AcceptLanguage    string `key:"accept_language" description:"Accept-Language header to send, e.g. fr or fr-CA, fr;q=0.8"`
+	Referer           string `key:"referer" description:"Referer header to send"`
+	Origin            string `key:"origin" description:"Origin header to send; the response must allow it in Access-Control-Allow-Origin"`
+	SignSecret        string `key:"sign_secret" description:"HMAC secret; when set, the request is signed"`
+	SignAlgorithm     string `key:"sign_algorithm" default:"sha256" enum:"sha1,sha256,sha512" description:"Hash used for the HMAC signature"`
+	SignTemplate      string `key:"sign_template" default:"{{.body}}" description:"Template of the string to sign, from .method, .path, .query, .host, .body, header \"Name\" and the time and nonce helpers"`
+	SignHeader        string `key:"sign_header" default:"X-Signature" description:"Header the signature is sent in"`
+	SignEncoding      string `key:"sign_encoding" default:"hex" enum:"hex,base64" description:"Encoding of the signature"`
+	SignPrefix        string `key:"sign_prefix" description:"Text put before the signature in the header, e.g. sha256="`
+	JWTAlgorithm      string `key:"jwt_algorithm" default:"HS256" enum:"HS256,RS256" description:"Algorithm jwt auth signs its token with, and validJwt requires"`
+	JWTKey            string `key:"jwt_key" description:"HS256 secret, or PEM-encoded RSA private key for RS256, for jwt auth"`
+	JWTClaims         string `key:"jwt_claims" description:"JSON object of claims for jwt auth; iat and exp are added unless set"`
+	JWTTTLSeconds     int    `key:"jwt_ttl_seconds" default:"300" description:"Lifetime of the token jwt auth mints"`
+	JWTVerifyKey      string `key:"jwt_verify_key" description:"HS256 secret, or PEM-encoded RSA public key or certificate for RS256, that validJwt verifies with"`
+	JWTSource         string `key:"jwt_source" default:"json" enum:"json,header,cookie" description:"Where validJwt finds the token in the response"`
+	JWTField          string `key:"jwt_field" default:"token" description:"JSON path, header or cookie name holding the token for validJwt"`
+	RedactHeaders     string `key:"redact_headers" description:"Comma-separated headers, beyond Authorization, Cookie and the like, whose values are secret and redacted from errors"`
+	MaxPages          int    `key:"max_pages" default:"10" description:"Pages pagination follows through Link rel=\"next\" headers, counting the first, before the chain must have ended"`
+	PageItemsPath     string `key:"page_items_path" description:"Path to the JSON array of items on each page pagination counts toward expected_output; empty counts a top-level array"`
+	LinkPath          string `key:"link_path" description:"Path to the link in the JSON response that followLink follows: a URL string or an object with an href"`
+	LinkMatchType     string `key:"link_match_type" default:"substringMatch" description:"Match type followLink applies, with expected_output, to the response the link leads to"`
+	CallbackListen    string `key:"callback_listen" default:":0" description:"Address webhook listens on for the target's callback; port 0 picks a free port"`
+	CallbackHost      string `key:"callback_host" description:"Host the target should call back to; defaults to the local address that routes to the url host"`
+	CallbackTimeoutMs int    `key:"callback_timeout_ms" default:"10000" description:"How long webhook waits for the callback after the trigger request"`
+	DialMap           string `key:"dial_map" description:"Addresses to dial in place of those urls and redirects name, one \"host:port=address:port\" per line, e.g. app.internal:443=10.0.5.2:443"`
+	IPFamily          string `key:"ip_family" default:"any" enum:"any,ipv4,ipv6" description:"Connect only over IPv4 or IPv6, so a target with both A and AAAA records is checked the same way from every host"`
+	HappyEyeballsMs   int    `key:"happy_eyeballs_ms" description:"With ip_family any, how long to try the first address family before racing the other; 0 uses the default 300ms, negative tries addresses one at a time"`
+	BodyTimeoutMs     int    `key:"body_timeout_ms" description:"Fail if reading the response body takes more than this many milliseconds in all, even while bytes trickle in often enough never to trip read_idle_timeout_ms; 0 disables"`
+	ExpectIssuer      string `key:"expect_issuer" description:"Issuer DN the leaf certificate must have, e.g. CN=Competition CA,O=Scoring, or a regex it must match in full; requires insecure to be false"`
+	ProxyURL          string `key:"proxy_url" description:"HTTP proxy every request is sent through, e.g. http://proxy.internal:3128; empty connects directly"`
+	ConnectTimeoutMs  int    `key:"connect_timeout_ms" description:"Fail if opening the connection, TLS handshake included, takes longer than this many milliseconds; 0 uses the 30s default"`
+	HeaderTimeoutMs   int    `key:"header_timeout_ms" description:"Fail if the response headers take longer than this many milliseconds to arrive after the request is sent; 0 disables"`
+	TunnelVerb        string `key:"tunnel_verb" default:"GET" enum:"GET,POST,PUT,DELETE,PATCH,HEAD,OPTIONS" description:"With verb CONNECT, the HTTP method sent to tunnel_url through the tunnel, with body, content_type and headers"`
 }
 
 func Validate(config string) error {
-	conf := Schema{}
+	conf, err := decodeConfig(config)
+	if err != nil {
+		return err
+	}
 
-	err := schema.Unmarshal([]byte(config), &conf)
+	conf, err = expandEnv(conf)
 	if err != nil {
 		return err
 	}
 
-	if conf.URL == "" {
+	return redactError(conf, validate(conf))
+}
+
+func validate(conf Schema) error {
+	var err error
+
+	if len(targets(conf)) == 0 {
 		return fmt.Errorf("url must be provided; got: %v", conf.URL)
 	}
 
+	for _, field := range [][2]string{
+		{"url", conf.URL},
+		{"fallback_url", conf.FallbackURL},
+		{"body", conf.Body},
+		{"headers", conf.Headers},
+		{"request_headers", conf.RequestHeaders},
+	} {
+		if strings.Contains(field[1], "{{") {
+			_, err = parseTemplate(field[0], field[1])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if !slices.Contains([]string{"all", "any", "round_robin"}, conf.URLPolicy) {
+		return fmt.Errorf("invalid url_policy provided: %v", conf.URLPolicy)
+	}
+
 	if !slices.Contains([]string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS", "CONNECT", "TRACE"}, conf.Verb) {
 		return fmt.Errorf("invalid command provided: %v", conf.Verb)
 	}
 
-	if !slices.Contains([]string{"statusCode", "substringMatch", "exactMatch", "regexMatch"}, conf.MatchType) {
-		return fmt.Errorf("invalid match type provided: %v", conf.MatchType)
+	if conf.Verb == "CONNECT" && conf.TunnelURL == "" {
+		return fmt.Errorf("tunnel_url must be provided when verb is CONNECT")
 	}
 
-	if conf.ExpectedOutput == "" {
-		return fmt.Errorf("expected_output must be provided; got: %v", conf.ExpectedOutput)
+	if conf.Verb == "CONNECT" && !slices.Contains([]string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"}, conf.TunnelVerb) {
+		return fmt.Errorf("invalid tunnel_verb provided: %v", conf.TunnelVerb)
 	}
 
-	if conf.MatchType == "statusCode" {
-		status_code, err := strconv.Atoi(conf.ExpectedOutput)
-		if err != nil {
-			return fmt.Errorf("invalid status code provided: %v; %q", conf.ExpectedOutput, err)
-		}
+	matcher, ok := lookupMatcher(conf.MatchType)
+	if !ok {
+		return fmt.Errorf("invalid match type provided: %v", conf.MatchType)
+	}
 
-		if status_code < 100 || status_code > 599 {
-			return fmt.Errorf("invalid status code provided: %d", status_code)
+	if v, ok := matcher.(ConfigValidator); ok {
+		err = v.ValidateConfig(conf)
+		if err != nil {
+			return err
 		}
+	} else if conf.ExpectedOutput == "" {
+		return fmt.Errorf("expected_output must be provided; got: %v", conf.ExpectedOutput)
 	}
 
 	if conf.Headers != "" {
@@ -73,6 +232,172 @@ func Validate(config string) error {
 		}
 	}
 
+	_, err = parseHeaderLines(conf.RequestHeaders)
+	if err != nil {
+		return err
+	}
+
+	if conf.Cookies != "" {
+		_, err = http.ParseCookie(conf.Cookies)
+		if err != nil {
+			return fmt.Errorf("cookie format must be \"name=value; name=value\" ; got: %v", conf.Cookies)
+		}
+	}
+
+	_, err = parseLabels(conf.ArtifactLabels)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains([]string{"off", "flag", "fail"}, conf.ChangeDetection) {
+		return fmt.Errorf("invalid change_detection provided: %v", conf.ChangeDetection)
+	}
+
+	if conf.ExpectALPN != "" && conf.ExpectALPN != "h2" && conf.ExpectALPN != "http/1.1" {
+		return fmt.Errorf("expect_alpn must be h2 or http/1.1; got: %v", conf.ExpectALPN)
+	}
+
+	if conf.FinalURLPattern != "" {
+		_, err = regexp.Compile(conf.FinalURLPattern)
+		if err != nil {
+			return fmt.Errorf("invalid final_url_pattern provided: %v; %q", conf.FinalURLPattern, err)
+		}
+	}
+
+	if conf.ExpectIssuer != "" {
+		_, err = regexp.Compile("^(?:" + conf.ExpectIssuer + ")$")
+		if err != nil {
+			return fmt.Errorf("invalid expect_issuer provided: %v; %q", conf.ExpectIssuer, err)
+		}
+
+		if conf.Insecure {
+			return fmt.Errorf("expect_issuer requires insecure to be false, since an unverified certificate can name any issuer")
+		}
+	}
+
+	if conf.ExpectContentType != "" {
+		_, err = regexp.Compile("^(?:" + conf.ExpectContentType + ")$")
+		if err != nil {
+			return fmt.Errorf("invalid expect_content_type provided: %v; %q", conf.ExpectContentType, err)
+		}
+	}
+
+	if conf.MaxMetaRefresh < 0 {
+		return fmt.Errorf("max_meta_refresh must not be negative; got: %d", conf.MaxMetaRefresh)
+	}
+
+	if conf.AssetSamples < 0 {
+		return fmt.Errorf("asset_samples must not be negative; got: %d", conf.AssetSamples)
+	}
+
+	_, err = parseStatusScores(conf.StatusScores)
+	if err != nil {
+		return err
+	}
+
+	if conf.Retries < 0 {
+		return fmt.Errorf("retries must not be negative; got: %d", conf.Retries)
+	}
+
+	if conf.RetryDelayMs < 0 {
+		return fmt.Errorf("retry_delay_ms must not be negative; got: %d", conf.RetryDelayMs)
+	}
+
+	_, err = parseStatusList(conf.RetryOnStatus)
+	if err != nil {
+		return err
+	}
+
+	if conf.JitterMs < 0 {
+		return fmt.Errorf("jitter_ms must not be negative; got: %d", conf.JitterMs)
+	}
+
+	if conf.RealIP != "" && net.ParseIP(conf.RealIP) == nil {
+		return fmt.Errorf("invalid real_ip provided: %v", conf.RealIP)
+	}
+
+	for _, hop := range splitList(conf.ForwardedFor) {
+		if net.ParseIP(hop) == nil {
+			return fmt.Errorf("invalid forwarded_for address provided: %v", hop)
+		}
+	}
+
+	if conf.Referer != "" {
+		_, err = url.Parse(conf.Referer)
+		if err != nil {
+			return fmt.Errorf("invalid referer provided: %v; %q", conf.Referer, err)
+		}
+	}
+
+	if conf.Origin != "" {
+		origin, err := url.Parse(conf.Origin)
+		if err != nil || origin.Scheme == "" || origin.Host == "" {
+			return fmt.Errorf("origin must be a scheme and host such as https://example.com; got: %v", conf.Origin)
+		}
+	}
+
+	if conf.AcceptNegotiated && conf.Accept == "" {
+		return fmt.Errorf("accept_negotiated requires accept to be provided")
+	}
+
+	if conf.IdempotencyReplay && !conf.IdempotencyKey {
+		return fmt.Errorf("idempotency_replay requires idempotency_key to be set")
+	}
+
+	if conf.SignSecret != "" {
+		err = validateSigning(conf)
+		if err != nil {
+			return err
+		}
+	}
+
+	if conf.MaxRedirects < 0 {
+		return fmt.Errorf("max_redirects must not be negative; got: %d", conf.MaxRedirects)
+	}
+
+	if !slices.Contains([]string{"any", "ipv4", "ipv6"}, conf.IPFamily) {
+		return fmt.Errorf("invalid ip_family provided: %v", conf.IPFamily)
+	}
+
+	_, err = parseDialMap(conf.DialMap)
+	if err != nil {
+		return err
+	}
+
+	if conf.MaxTLSHandshakeMs < 0 {
+		return fmt.Errorf("max_tls_handshake_ms must not be negative; got: %d", conf.MaxTLSHandshakeMs)
+	}
+
+	if conf.ProxyURL != "" {
+		proxy, err := url.Parse(conf.ProxyURL)
+		if err != nil || (proxy.Scheme != "http" && proxy.Scheme != "https") || proxy.Host == "" {
+			return fmt.Errorf("proxy_url must be an http or https URL; got: %v", conf.ProxyURL)
+		}
+	}
+
+	if conf.ConnectTimeoutMs < 0 {
+		return fmt.Errorf("connect_timeout_ms must not be negative; got: %d", conf.ConnectTimeoutMs)
+	}
+
+	if conf.HeaderTimeoutMs < 0 {
+		return fmt.Errorf("header_timeout_ms must not be negative; got: %d", conf.HeaderTimeoutMs)
+	}
+
+	if conf.ReadIdleTimeoutMs < 0 {
+		return fmt.Errorf("read_idle_timeout_ms must not be negative; got: %d", conf.ReadIdleTimeoutMs)
+	}
+
+	if conf.BodyTimeoutMs < 0 {
+		return fmt.Errorf("body_timeout_ms must not be negative; got: %d", conf.BodyTimeoutMs)
+	}
+
+	if conf.Range != "" {
+		_, _, err = parseByteRange(conf.Range)
+		if err != nil {
+			return err
+		}
+	}
+
 	if conf.ContentType == "empty" && conf.Body != "" {
 		return fmt.Errorf("body must not be provided when using empty Content-Type; got: %v", conf.Body)
 	}
@@ -85,52 +410,424 @@ func Validate(config string) error {
 		return fmt.Errorf("invalid content type provided: %v", conf.ContentType)
 	}
 
+	if _, ok := lookupAuth(conf.Auth); !ok {
+		return fmt.Errorf("invalid auth provided: %v", conf.Auth)
+	}
+
+	if conf.Auth == "jwt" {
+		err = validateJWT(conf)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !slices.Contains([]string{"single", "load", "burst", "rateLimit", "consecutive", "sample"}, conf.Mode) {
+		return fmt.Errorf("invalid mode provided: %v", conf.Mode)
+	}
+
+	if conf.Mode == "load" {
+		err = validateLoad(conf)
+		if err != nil {
+			return err
+		}
+	}
+
+	if conf.Mode == "rateLimit" && conf.Samples < 1 {
+		return fmt.Errorf("samples must be at least 1; got: %d", conf.Samples)
+	}
+
+	if conf.Mode == "burst" {
+		err = validateBurst(conf)
+		if err != nil {
+			return err
+		}
+	}
+
+	if conf.Mode == "consecutive" {
+		err = validateConsecutive(conf)
+		if err != nil {
+			return err
+		}
+	}
+
+	if conf.Mode == "sample" {
+		err = validateSample(conf)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func Run(ctx context.Context, config string) error {
-	conf := Schema{}
+	return defaultChecker.Run(ctx, config)
+}
+
+func (c *Checker) Run(ctx context.Context, config string) error {
+	_, err := c.Check(ctx, config)
+	return err
+}
+
+// Check runs config like Run and also returns what the check observed. The
+// result is non-nil whenever config parses, even if the check fails.
+func (c *Checker) Check(ctx context.Context, config string) (*Result, error) {
+	conf, err := decodeConfig(config)
+	if err != nil {
+		c.emitReport(conf, nil, err)
+		return nil, err
+	}
+
+	res, err := c.check(ctx, conf)
+	c.emitReport(conf, res, err)
+
+	return res, err
+}
+
+func (c *Checker) check(ctx context.Context, conf Schema) (res *Result, err error) {
+	conf, err = expandEnv(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		err = redactError(conf, err)
+		if res != nil {
+			for i, note := range res.Notes {
+				res.Notes[i] = redact(conf, note)
+			}
+		}
+	}()
+
+	// URLs calling nonce or time helpers are left for renderRequest to
+	// resolve afresh for every request.
+	if !perRequest(conf.URL) {
+		conf.URL, err = resolveURL(ctx, conf.URL, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.FallbackURL != "" && !perRequest(conf.FallbackURL) {
+		conf.FallbackURL, err = resolveURL(ctx, conf.FallbackURL, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.MatchType == "webhook" {
+		var stop func()
+		ctx, stop, err = listenCallback(ctx, conf)
+		if err != nil {
+			return nil, err
+		}
+		defer stop()
+	}
+
+	// Spread out checks that a scoring round starts all at once.
+	if conf.JitterMs > 0 {
+		err = sleep(ctx, rand.N(time.Duration(conf.JitterMs+1)*time.Millisecond))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res = &Result{}
+
+	err = c.runTargets(ctx, conf, res)
+
+	return res, err
+}
+
+// presetRequest fills in the request for match types that define it
+// themselves rather than taking it from the config.
+func presetRequest(conf Schema) Schema {
+	switch conf.MatchType {
+	case "healthcheck":
+		conf = healthcheckRequest(conf)
+	case "uploadIntegrity":
+		conf = uploadRequest(conf)
+	case "login", "logoutInvalidates":
+		conf = loginRequest(conf)
+	case "rejectsBadCredentials":
+		conf = badCredentialsRequest(conf)
+	}
+
+	return conf
+}
 
-	err := schema.Unmarshal([]byte(config), &conf)
+// runMode runs a single-URL config according to its mode.
+func (c *Checker) runMode(ctx context.Context, conf Schema, res *Result) error {
+	var err error
+
+	conf = presetRequest(conf)
+
+	switch conf.Mode {
+	case "single":
+		err = c.runRetries(ctx, conf, res)
+	case "load":
+		err = c.runLoad(ctx, conf, res)
+	case "burst":
+		err = c.runBurst(ctx, conf, res)
+	case "rateLimit":
+		err = c.runRateLimit(ctx, conf, res)
+	case "consecutive":
+		err = c.runConsecutive(ctx, conf, res)
+	case "sample":
+		err = c.runSample(ctx, conf, res)
+	default:
+		err = fmt.Errorf("invalid mode provided: %v", conf.Mode)
+	}
+
+	return err
+}
+
+// attempt sends the configured request once and matches the response.
+func (c *Checker) attempt(ctx context.Context, conf Schema, res *Result) error {
+	matcher, ok := lookupMatcher(conf.MatchType)
+	if !ok {
+		return fmt.Errorf("invalid match type provided: %v", conf.MatchType)
+	}
+
+	ex, err := c.exchange(ctx, conf, res)
 	if err != nil {
+		var reqErr *requestError
+		if errors.As(err, &reqErr) {
+			recordTimings(res, reqErr.timings)
+		}
 		return err
 	}
+	// The deferred closure drains whichever response meta refresh left.
+	defer func() { drainBody(ex.Response.Body) }()
+
+	err = followMetaRefresh(ctx, ex)
+	if err == nil {
+		err = matcher.Match(ctx, ex)
+	}
+	observe(ex)
+	if err == nil {
+		err = assert(ctx, ex)
+	}
+	if err == nil {
+		err = c.detectChange(ex)
+	}
+	c.saveArtifact(ctx, ex, err)
+
+	return err
+}
+
+// exchange sends the configured request, answering one auth challenge if the
+// provider supports it. The caller must drain the response body.
+func (c *Checker) exchange(ctx context.Context, conf Schema, res *Result) (*Exchange, error) {
+	if conf.ReadIdleTimeoutMs <= 0 && conf.BodyTimeoutMs <= 0 {
+		return c.sendWithFallback(ctx, conf, res)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	ex, err := c.sendWithFallback(ctx, conf, res)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	ex.Response.Body = withBodyTimeouts(ex.Response.Body, conf, cancel)
+
+	return ex, nil
+}
+
+func (c *Checker) send(ctx context.Context, conf Schema, res *Result) (*Exchange, error) {
+	if conf.Verb == http.MethodConnect {
+		return c.sendTunnel(ctx, conf, res)
+	}
+
+	req, provider, err := prepareRequest(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	client := c.httpClient(conf)
+	trace := &tracer{}
+
+	resp, err := client.Do(trace.attach(req))
+	if err != nil {
+		return nil, failedRequest(err, trace)
+	}
+
+	if responder, ok := provider.(ChallengeResponder); ok && resp.StatusCode == http.StatusUnauthorized {
+		req, err = buildRequest(ctx, conf)
+		if err != nil {
+			drainBody(resp.Body)
+			return nil, err
+		}
+
+		retry, err := responder.Challenge(ctx, conf, req, resp)
+		drainBody(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("encountered error while answering auth challenge: %v", err)
+		}
+		if !retry {
+			return nil, fmt.Errorf("expected status code: %d; got: %d", http.StatusOK, http.StatusUnauthorized)
+		}
+
+		trace = &tracer{}
+
+		resp, err = client.Do(trace.attach(req))
+		if err != nil {
+			return nil, failedRequest(err, trace)
+		}
+	}
+
+	body, err := requestBody(req)
+	if err != nil {
+		drainBody(resp.Body)
+		return nil, err
+	}
+
+	return &Exchange{
+		Config:      conf,
+		Request:     req,
+		Response:    resp,
+		Client:      client,
+		Result:      res,
+		RequestBody: body,
+		tracer:      trace,
+	}, nil
+}
+
+// failedRequest classifies an error from sending a request. A redirect
+// loop is a definite misconfiguration and is returned as is; anything else
+// means no response was received.
+func failedRequest(err error, trace *tracer) error {
+	var loop *redirectLoopError
+	if errors.As(err, &loop) {
+		return loop
+	}
+
+	trace.failed()
+	return &requestError{err: err, timings: trace.snapshot()}
+}
+
+// requestError is a failure to get any response at all, such as a refused
+// connection or timeout, as opposed to a response that fails the check.
+type requestError struct {
+	err     error
+	timings Timings
+}
+
+func (e *requestError) Error() string {
+	return fmt.Sprintf("encounted error while making request: %v; timings: %v", e.err.Error(), e.timings)
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+// prepareRequest builds the request described by conf and applies its auth
+// provider, returning the provider for any later challenge.
+func prepareRequest(ctx context.Context, conf Schema) (*http.Request, AuthProvider, error) {
+	method, err := requestMethod(conf.Verb)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return prepareMethodRequest(ctx, conf, method)
+}
+
+// prepareMethodRequest is prepareRequest sending method in place of verb.
+func prepareMethodRequest(ctx context.Context, conf Schema, method string) (*http.Request, AuthProvider, error) {
+	provider, ok := lookupAuth(conf.Auth)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid auth provided: %v", conf.Auth)
+	}
+
+	req, err := buildMethodRequest(ctx, conf, method)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = provider.Authenticate(ctx, conf, req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encountered error while authenticating request: %v", err)
+	}
+
+	return req, provider, nil
+}
+
+// requestBody returns a copy of the body req sends, or nil if it has none.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.GetBody == nil {
+		return nil, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("encountered error while rendering request body: %v", err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("encountered error while rendering request body: %v", err)
+	}
+
+	return data, nil
+}
+
+func buildRequest(ctx context.Context, conf Schema) (*http.Request, error) {
+	method, err := requestMethod(conf.Verb)
+	if err != nil {
+		return nil, err
+	}
 
-	var requestType string
+	return buildMethodRequest(ctx, conf, method)
+}
 
-	switch conf.Verb {
+func requestMethod(verb string) (string, error) {
+	switch verb {
 	case "GET":
-		requestType = http.MethodGet
+		return http.MethodGet, nil
 	case "POST":
-		requestType = http.MethodPost
+		return http.MethodPost, nil
 	case "PUT":
-		requestType = http.MethodPut
+		return http.MethodPut, nil
 	case "DELETE":
-		requestType = http.MethodDelete
+		return http.MethodDelete, nil
 	case "PATCH":
-		requestType = http.MethodPatch
+		return http.MethodPatch, nil
 	case "HEAD":
-		requestType = http.MethodHead
+		return http.MethodHead, nil
 	case "OPTIONS":
-		requestType = http.MethodOptions
+		return http.MethodOptions, nil
 	case "CONNECT":
-		requestType = http.MethodConnect
+		return http.MethodConnect, nil
 	case "TRACE":
-		requestType = http.MethodTrace
+		return http.MethodTrace, nil
 	default:
-		return fmt.Errorf("provided invalid command/http verb: %q", conf.Verb)
+		return "", fmt.Errorf("provided invalid command/http verb: %q", verb)
+	}
+}
+
+// buildMethodRequest builds the request conf describes, sent with method
+// whatever its verb.
+func buildMethodRequest(ctx context.Context, conf Schema, method string) (*http.Request, error) {
+	now := time.Now()
+	conf, err := renderRequest(ctx, conf, now)
+	if err != nil {
+		return nil, err
 	}
+
 	var req *http.Request
 	if conf.ContentType == "empty" {
-		req, err = http.NewRequestWithContext(ctx, requestType, conf.URL, nil)
+		req, err = http.NewRequestWithContext(ctx, method, conf.URL, nil)
 		if err != nil {
-			return fmt.Errorf("encounted error while creating request: %v", err.Error())
+			return nil, fmt.Errorf("encounted error while creating request: %v", err.Error())
 		}
 
 	} else {
-		req, err = http.NewRequestWithContext(ctx, requestType, conf.URL, bytes.NewBufferString(conf.Body))
+		req, err = http.NewRequestWithContext(ctx, method, conf.URL, bytes.NewBufferString(conf.Body))
 		if err != nil {
-			return fmt.Errorf("encounted error while creating request: %v", err.Error())
+			return nil, fmt.Errorf("encounted error while creating request: %v", err.Error())
 		}
 		req.Header.Add("Content-Type", conf.ContentType)
 	}
@@ -141,74 +838,107 @@ func Run(ctx context.Context, config string) error {
 			for _, element := range headers {
 				keyvalue := strings.Split(element, ":")
 				if len(keyvalue) != 2 {
-					return fmt.Errorf("header format must be \"header:value;header:value\" ; got: %v", conf.Headers)
+					return nil, fmt.Errorf("header format must be \"header:value;header:value\" ; got: %v", conf.Headers)
 				}
 				req.Header.Add(keyvalue[0], keyvalue[1])
 			}
 		} else {
 			keyvalue := strings.Split(conf.Headers, ":")
 			if len(keyvalue) != 2 {
-				return fmt.Errorf("header format must be \"header:value;header:value\" ; got: %v", conf.Headers)
+				return nil, fmt.Errorf("header format must be \"header:value;header:value\" ; got: %v", conf.Headers)
 			}
 			req.Header.Add(keyvalue[0], keyvalue[1])
 		}
 	}
 
-	tls_config := &tls.Config{InsecureSkipVerify: conf.Insecure}
-	http_transpot := &http.Transport{TLSClientConfig: tls_config}
-	client := &http.Client{Transport: http_transpot}
+	headers, err := parseHeaderLines(conf.RequestHeaders)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
+	if conf.Range != "" {
+		req.Header.Set("Range", conf.Range)
+	}
+
+	if conf.Accept != "" {
+		req.Header.Set("Accept", conf.Accept)
+	}
+
+	if conf.AcceptLanguage != "" {
+		req.Header.Set("Accept-Language", conf.AcceptLanguage)
+	}
+
+	if conf.Referer != "" {
+		req.Header.Set("Referer", conf.Referer)
+	}
+
+	if conf.Origin != "" {
+		req.Header.Set("Origin", conf.Origin)
+	}
+
+	if conf.ForwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", conf.ForwardedFor)
+	}
+
+	if conf.RealIP != "" {
+		req.Header.Set("X-Real-IP", conf.RealIP)
+	}
 
-	resp, err := client.Do(req)
+	if conf.Forwarded != "" {
+		req.Header.Set("Forwarded", conf.Forwarded)
+	}
+
+	err = setRequestID(conf, req)
 	if err != nil {
-		return fmt.Errorf("encounted error while making request: %v", err.Error())
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	switch conf.MatchType {
-	case "statusCode":
-		status_code, err := strconv.Atoi(conf.ExpectedOutput)
-		if err != nil {
-			return fmt.Errorf("invalid status code provided: %v; %q", conf.ExpectedOutput, err)
-		}
+	err = setIdempotencyKey(conf, req)
+	if err != nil {
+		return nil, err
+	}
 
-		if resp.StatusCode != status_code {
-			return fmt.Errorf("expected status code: %d; got: %d", status_code, resp.StatusCode)
-		}
-	case "substringMatch":
-		body, err := io.ReadAll(resp.Body)
+	if conf.Cookies != "" {
+		cookies, err := http.ParseCookie(conf.Cookies)
 		if err != nil {
-			return fmt.Errorf("encountered error while reading response body: %v", err)
-		}
-
-		if !strings.Contains(string(body), conf.ExpectedOutput) {
-			return fmt.Errorf("expected output not found in response body")
+			return nil, fmt.Errorf("cookie format must be \"name=value; name=value\" ; got: %v", conf.Cookies)
 		}
-	case "exactMatch":
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("encountered error while reading response body: %v", err)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
 		}
+	}
 
-		if string(body) != conf.ExpectedOutput {
-			return fmt.Errorf("expected output not found in response body")
-		}
-	case "regexMatch":
-		pattern, err := regexp.Compile(conf.ExpectedOutput)
-		if err != nil {
-			return fmt.Errorf("invalid regex pattern provided: %v; %q", conf.ExpectedOutput, err)
-		}
+	err = signRequest(conf, req, now)
+	if err != nil {
+		return nil, err
+	}
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("encountered error while reading response body: %v", err)
+	return req, nil
+}
+
+// parseHeaderLines parses headers written one "Header: value" per line. Unlike
+// the legacy headers field, values may contain colons and semicolons.
+func parseHeaderLines(raw string) (http.Header, error) {
+	headers := http.Header{}
+
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
 		}
 
-		if !pattern.Match(body) {
-			return fmt.Errorf("expected output not found in response body")
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("request_headers lines must be \"Header: value\" ; got: %v", line)
 		}
-	default:
-		return fmt.Errorf("invalid match type provided: %v", conf.MatchType)
+
+		headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
 	}
 
-	return nil
+	return headers, nil
 }
@@ -0,0 +1,338 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Exchange is a completed request/response pair handed to a Matcher.
+type Exchange struct {
+	Config   Schema
+	Request  *http.Request
+	Response *http.Response
+	Client   *http.Client
+	Result   *Result
+
+	// RequestBody is the body Request was sent with, after its templates
+	// were filled.
+	RequestBody []byte
+
+	tracer  *tracer
+	body    []byte
+	bodyErr error
+	read    bool
+}
+
+// Body reads the response body on first use and returns the cached bytes
+// afterwards, so several assertions can inspect it.
+func (e *Exchange) Body() ([]byte, error) {
+	if !e.read {
+		e.body, e.bodyErr = io.ReadAll(e.Response.Body)
+		e.read = true
+		if e.tracer != nil {
+			e.tracer.bodyRead()
+		}
+	}
+
+	return e.body, e.bodyErr
+}
+
+// Timings returns the phase durations measured for Request.
+func (e *Exchange) Timings() Timings {
+	if e.tracer == nil {
+		return Timings{}
+	}
+
+	return e.tracer.snapshot()
+}
+
+// NewRequest builds a fresh copy of the configured request, with auth
+// applied, for matchers that need to send follow-up requests through Client.
+func (e *Exchange) NewRequest(ctx context.Context) (*http.Request, error) {
+	req, _, err := prepareRequest(ctx, e.Config)
+	return req, err
+}
+
+// probeRequest builds the configured request, headers and auth included, to
+// the URL Request was sent to, but with method and, unless withBody, no body.
+func (e *Exchange) probeRequest(ctx context.Context, method string, withBody bool) (*http.Request, error) {
+	conf := e.Config
+	conf.URL = e.Request.URL.String()
+	if !withBody {
+		conf.ContentType = "empty"
+	}
+
+	req, _, err := prepareMethodRequest(ctx, conf, method)
+	return req, err
+}
+
+// Matcher decides whether an exchange satisfies the check. A nil error is a
+// pass.
+type Matcher interface {
+	Match(ctx context.Context, ex *Exchange) error
+}
+
+// MatcherFunc adapts an ordinary function to a Matcher.
+type MatcherFunc func(ctx context.Context, ex *Exchange) error
+
+func (f MatcherFunc) Match(ctx context.Context, ex *Exchange) error {
+	return f(ctx, ex)
+}
+
+// ConfigValidator may be implemented by a Matcher to reject configs that
+// cannot work with it before any request is made. Matchers that implement it
+// are responsible for checking expected_output themselves; for all others
+// Validate requires it to be non-empty.
+type ConfigValidator interface {
+	ValidateConfig(conf Schema) error
+}
+
+var (
+	matchersMu sync.RWMutex
+	matchers   = map[string]Matcher{}
+)
+
+// RegisterMatcher makes m available as match_type name. It panics if name is
+// empty or already registered.
+func RegisterMatcher(name string, m Matcher) {
+	matchersMu.Lock()
+	defer matchersMu.Unlock()
+
+	if name == "" || m == nil {
+		panic("http: RegisterMatcher called with empty name or nil matcher")
+	}
+	if _, dup := matchers[name]; dup {
+		panic("http: RegisterMatcher called twice for " + name)
+	}
+
+	matchers[name] = m
+}
+
+// Matchers returns the registered match type names in sorted order.
+func Matchers() []string {
+	matchersMu.RLock()
+	defer matchersMu.RUnlock()
+
+	names := make([]string, 0, len(matchers))
+	for name := range matchers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func lookupMatcher(name string) (Matcher, bool) {
+	matchersMu.RLock()
+	defer matchersMu.RUnlock()
+
+	m, ok := matchers[name]
+	return m, ok
+}
+
+// fetch GETs target with the headers of the original request, for matchers
+// that follow links found in the response. The caller must drain the body.
+func (e *Exchange) fetch(ctx context.Context, target string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("encounted error while creating request: %v", err.Error())
+	}
+
+	req.Header = e.Request.Header.Clone()
+	req.Header.Del("Content-Type")
+	req.Header.Del("Range")
+
+	// Credentials only go back to the host they were configured for.
+	if req.URL.Host != e.Request.URL.Host {
+		req.Header.Del("Authorization")
+		req.Header.Del("Cookie")
+	}
+
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("encounted error while making request: %v", err.Error())
+	}
+
+	return resp, nil
+}
+
+// followUp builds a bodiless request to target with the configured headers
+// and auth, for matchers that act on a resource the check created.
+func (e *Exchange) followUp(ctx context.Context, method string, target string) (*http.Request, error) {
+	req, err := e.NewRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	host := req.URL.Host
+	req.URL, err = req.URL.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("encounted error while creating request: %v", err.Error())
+	}
+	req.Host = ""
+	req.Method = method
+	req.Body, req.GetBody, req.ContentLength = nil, nil, 0
+	req.Header.Del("Content-Type")
+
+	if req.URL.Host != host {
+		req.Header.Del("Authorization")
+		req.Header.Del("Cookie")
+	}
+
+	return req, nil
+}
+
+func readBody(ex *Exchange) ([]byte, error) {
+	body, err := ex.Body()
+	if err != nil {
+		return nil, fmt.Errorf("encountered error while reading response body: %v", err)
+	}
+
+	return body, nil
+}
+
+type statusCodeMatcher struct{}
+
+func (statusCodeMatcher) ValidateConfig(conf Schema) error {
+	if conf.ExpectedOutput == "" {
+		return fmt.Errorf("expected_output must be provided; got: %v", conf.ExpectedOutput)
+	}
+
+	status_code, err := strconv.Atoi(conf.ExpectedOutput)
+	if err != nil {
+		return fmt.Errorf("invalid status code provided: %v; %q", conf.ExpectedOutput, err)
+	}
+
+	if status_code < 100 || status_code > 599 {
+		return fmt.Errorf("invalid status code provided: %d", status_code)
+	}
+
+	return nil
+}
+
+func (statusCodeMatcher) Match(ctx context.Context, ex *Exchange) error {
+	status_code, err := strconv.Atoi(ex.Config.ExpectedOutput)
+	if err != nil {
+		return fmt.Errorf("invalid status code provided: %v; %q", ex.Config.ExpectedOutput, err)
+	}
+
+	if ex.Response.StatusCode != status_code {
+		return fmt.Errorf("expected status code: %d; got: %d", status_code, ex.Response.StatusCode)
+	}
+
+	return nil
+}
+
+func matchSubstring(ctx context.Context, ex *Exchange) error {
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(string(body), ex.Config.ExpectedOutput) {
+		return fmt.Errorf("expected output not found in response body")
+	}
+
+	return nil
+}
+
+func matchExact(ctx context.Context, ex *Exchange) error {
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	if normalize(ex.Config, string(body)) != normalize(ex.Config, ex.Config.ExpectedOutput) {
+		return fmt.Errorf("expected output not found in response body")
+	}
+
+	return nil
+}
+
+// normalize applies exactMatch's optional newline and whitespace folding.
+func normalize(conf Schema, s string) string {
+	if conf.NormalizeNewlines {
+		s = strings.ReplaceAll(s, "\r\n", "\n")
+	}
+
+	if conf.TrimWhitespace {
+		s = strings.TrimSpace(s)
+	}
+
+	return s
+}
+
+func matchRegex(ctx context.Context, ex *Exchange) error {
+	pattern, err := regexp.Compile(ex.Config.ExpectedOutput)
+	if err != nil {
+		return fmt.Errorf("invalid regex pattern provided: %v; %q", ex.Config.ExpectedOutput, err)
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	if !pattern.Match(body) {
+		return fmt.Errorf("expected output not found in response body")
+	}
+
+	return nil
+}
+
+func init() {
+	RegisterMatcher("statusCode", statusCodeMatcher{})
+	RegisterMatcher("substringMatch", MatcherFunc(matchSubstring))
+	RegisterMatcher("exactMatch", MatcherFunc(matchExact))
+	RegisterMatcher("regexMatch", MatcherFunc(matchRegex))
+	RegisterMatcher("notModified", notModifiedMatcher{})
+	RegisterMatcher("partialContent", partialContentMatcher{})
+	RegisterMatcher("headConsistent", headConsistentMatcher{})
+	RegisterMatcher("corsPreflight", corsPreflightMatcher{})
+	RegisterMatcher("compressed", compressedMatcher{})
+	RegisterMatcher("keepAlive", keepAliveMatcher{})
+	RegisterMatcher("methodBlocked", methodBlockedMatcher{})
+	RegisterMatcher("traceDisabled", traceDisabledMatcher{})
+	RegisterMatcher("validJson", validJSONMatcher{})
+	RegisterMatcher("validXml", validXMLMatcher{})
+	RegisterMatcher("htmlElement", htmlElementMatcher{})
+	RegisterMatcher("crawl", crawlMatcher{})
+	RegisterMatcher("sitemap", sitemapMatcher{})
+	RegisterMatcher("ocspStapled", ocspStapledMatcher{})
+	RegisterMatcher("hsts", hstsMatcher{})
+	RegisterMatcher("cookieSecurity", cookieSecurityMatcher{})
+	RegisterMatcher("serverBanner", serverBannerMatcher{})
+	RegisterMatcher("allSubstrings", MatcherFunc(matchAllSubstrings))
+	RegisterMatcher("anySubstring", MatcherFunc(matchAnySubstring))
+	RegisterMatcher("jsonEquals", jsonEqualsMatcher{})
+	RegisterMatcher("jsonSubset", jsonSubsetMatcher{})
+	RegisterMatcher("yamlPath", yamlPathMatcher{})
+	RegisterMatcher("csvValue", csvValueMatcher{})
+	RegisterMatcher("binaryMatch", binaryMatcher{})
+	RegisterMatcher("image", imageMatcher{})
+	RegisterMatcher("pdf", pdfMatcher{})
+	RegisterMatcher("subresourceIntegrity", sriMatcher{})
+	RegisterMatcher("healthcheck", healthcheckMatcher{})
+	RegisterMatcher("prometheus", prometheusMatcher{})
+	RegisterMatcher("crudRoundTrip", crudMatcher{})
+	RegisterMatcher("uploadIntegrity", uploadIntegrityMatcher{})
+	RegisterMatcher("login", loginMatcher{})
+	RegisterMatcher("logoutInvalidates", logoutMatcher{})
+	RegisterMatcher("rejectsBadCredentials", badCredentialsMatcher{})
+	RegisterMatcher("minVersion", minVersionMatcher{})
+	RegisterMatcher("vhostSweep", vhostSweepMatcher{})
+	RegisterMatcher("sniMismatch", sniMismatchMatcher{})
+	RegisterMatcher("cacheControl", cacheControlMatcher{})
+	RegisterMatcher("etag", etagMatcher{})
+	RegisterMatcher("validJwt", validJWTMatcher{})
+	RegisterMatcher("pagination", paginationMatcher{})
+	RegisterMatcher("followLink", followLinkMatcher{})
+	RegisterMatcher("webhook", webhookMatcher{})
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestBodyMatchers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Welcome to the scoreboard, version 1.2"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		matchType string
+		expected  string
+		want      string
+	}{
+		{"status", "statusCode", "200", ""},
+		{"wrong status", "statusCode", "404", "expected status code: 404; got: 200"},
+		{"substring", "substringMatch", "scoreboard", ""},
+		{"missing substring", "substringMatch", "flag", "expected output not found"},
+		{"exact", "exactMatch", "Welcome to the scoreboard, version 1.2", ""},
+		{"inexact", "exactMatch", "Welcome", "expected output not found"},
+		{"regex", "regexMatch", `version \d+\.\d+`, ""},
+		{"regex mismatch", "regexMatch", `version \d+\.\d+\.\d+`, "expected output not found"},
+		{"bad regex", "regexMatch", `version (`, "invalid regex pattern provided"},
+		{"unknown", "fuzzyMatch", "scoreboard", "invalid match type provided: fuzzyMatch"},
+		{"no expected output", "substringMatch", "", "expected_output must be provided"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runAgainst(t, server, map[string]any{"match_type": tt.matchType, "expected_output": tt.expected})
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+// validatedMatcher passes responses with the status expected_output names,
+// which it requires to be a 3-digit code.
+type validatedMatcher struct{}
+
+func (validatedMatcher) ValidateConfig(conf Schema) error {
+	if len(conf.ExpectedOutput) != 3 {
+		return fmt.Errorf("expected_output must be a status code; got: %q", conf.ExpectedOutput)
+	}
+	return nil
+}
+
+func (validatedMatcher) Match(ctx context.Context, ex *Exchange) error {
+	if fmt.Sprint(ex.Response.StatusCode) != ex.Config.ExpectedOutput {
+		return fmt.Errorf("got: %d", ex.Response.StatusCode)
+	}
+	return nil
+}
+
+func TestRegisteredMatcher(t *testing.T) {
+	if !slices.Contains(Matchers(), "testTeapot") {
+		RegisterMatcher("testTeapot", MatcherFunc(func(ctx context.Context, ex *Exchange) error {
+			if ex.Response.StatusCode != http.StatusTeapot {
+				return fmt.Errorf("not a teapot")
+			}
+			return nil
+		}))
+		RegisterMatcher("testValidated", validatedMatcher{})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		conf map[string]any
+		want string
+	}{
+		{"func", map[string]any{"match_type": "testTeapot", "expected_output": "teapot"}, ""},
+		{"func needs expected output", map[string]any{"match_type": "testTeapot"}, "expected_output must be provided"},
+		{"validator", map[string]any{"match_type": "testValidated", "expected_output": "418"}, ""},
+		{"validator rejects", map[string]any{"match_type": "testValidated", "expected_output": "teapot"}, `expected_output must be a status code; got: "teapot"`},
+		{"validator fails", map[string]any{"match_type": "testValidated", "expected_output": "200"}, "got: 418"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runAgainst(t, server, tt.conf)
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestRegisterMatcherPanics(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher string
+		m       Matcher
+	}{
+		{"empty name", "", validatedMatcher{}},
+		{"nil matcher", "testNil", nil},
+		{"duplicate", "statusCode", validatedMatcher{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterMatcher(%q) did not panic", tt.matcher)
+				}
+			}()
+			RegisterMatcher(tt.matcher, tt.m)
+		})
+	}
+}
+
+func TestMatchersSorted(t *testing.T) {
+	names := Matchers()
+	if !slices.IsSorted(names) {
+		t.Errorf("Matchers() = %v; want sorted", names)
+	}
+	for _, name := range []string{"statusCode", "substringMatch", "exactMatch", "regexMatch"} {
+		if !slices.Contains(names, name) {
+			t.Errorf("Matchers() is missing %s", name)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// methodBlockedMatcher sends forbidden_verb to the URL and expects the server
+// to refuse it rather than act on it.
+type methodBlockedMatcher struct{}
+
+func (methodBlockedMatcher) ValidateConfig(conf Schema) error {
+	if conf.ForbiddenVerb == "" || strings.ToUpper(conf.ForbiddenVerb) != conf.ForbiddenVerb {
+		return fmt.Errorf("forbidden_verb must be an upper-case method; got: %v", conf.ForbiddenVerb)
+	}
+
+	_, err := rejectStatuses(conf, nil)
+	return err
+}
+
+func (methodBlockedMatcher) Match(ctx context.Context, ex *Exchange) error {
+	allowed, err := rejectStatuses(ex.Config, []int{http.StatusForbidden, http.StatusMethodNotAllowed})
+	if err != nil {
+		return err
+	}
+
+	// Built with the forbidden method from the start, so a request signature
+	// covers the method actually sent.
+	req, err := ex.probeRequest(ctx, ex.Config.ForbiddenVerb, true)
+	if err != nil {
+		return err
+	}
+
+	resp, err := ex.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("encounted error while making %s request: %v", req.Method, err.Error())
+	}
+	defer drainBody(resp.Body)
+
+	if !slices.Contains(allowed, resp.StatusCode) {
+		return fmt.Errorf("expected %s to be refused with one of %v; got: %d", req.Method, allowed, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// rejectStatuses returns the configured reject_statuses, or defaults when the
+// field is empty.
+func rejectStatuses(conf Schema, defaults []int) ([]int, error) {
+	if conf.RejectStatuses == "" {
+		return defaults, nil
+	}
+
+	return parseStatusList(conf.RejectStatuses)
+}
+
+func parseStatusList(raw string) ([]int, error) {
+	codes := []int{}
+	for _, item := range splitList(raw) {
+		code, err := strconv.Atoi(item)
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("invalid status code provided: %v", item)
+		}
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
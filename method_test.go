@@ -0,0 +1,87 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// signedServer rejects requests whose X-Signature is not an HMAC of their
+// method and path, then answers the rest with status for DELETE.
+func signedServer(status int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write([]byte(r.Method + " " + r.URL.Path))
+		if r.Header.Get("X-Signature") != hex.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(status)
+		}
+	}))
+}
+
+func TestMethodBlocked(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		conf   map[string]any
+		want   string
+	}{
+		{"forbidden", http.StatusForbidden, nil, ""},
+		{"not allowed", http.StatusMethodNotAllowed, nil, ""},
+		{"accepted", http.StatusNoContent, nil, "expected DELETE to be refused with one of [403 405]; got: 204"},
+		{"custom statuses", http.StatusNotFound, map[string]any{"reject_statuses": "404, 410"}, ""},
+		{"custom statuses miss", http.StatusForbidden, map[string]any{"reject_statuses": "404"}, "got: 403"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := signedServer(tt.status)
+			defer server.Close()
+
+			conf := map[string]any{
+				"url":            server.URL + "/admin",
+				"match_type":     "methodBlocked",
+				"forbidden_verb": "DELETE",
+				"sign_secret":    "s3cret",
+				"sign_template":  "{{.method}} {{.path}}",
+			}
+			for key, value := range tt.conf {
+				conf[key] = value
+			}
+
+			checkError(t, runAgainst(t, server, conf), tt.want)
+		})
+	}
+}
+
+func TestParseStatusList(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []int
+		err  string
+	}{
+		{"403, 405", []int{403, 405}, ""},
+		{" 404 ,,", []int{404}, ""},
+		{"", []int{}, ""},
+		{"99", nil, "invalid status code provided: 99"},
+		{"600", nil, "invalid status code provided: 600"},
+		{"4xx", nil, "invalid status code provided: 4xx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseStatusList(tt.raw)
+			checkError(t, err, tt.err)
+			if tt.err == "" && !slices.Equal(got, tt.want) {
+				t.Errorf("parseStatusList(%q) = %v; want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Migrate converts a config using the legacy "header:value;header:value"
+// headers string into request_headers and cookies. Cookie headers are moved to
+// cookies; everything else becomes one request_headers line. Other fields are
+// left untouched, and a config without legacy headers is returned as-is. The
+// migrated config must pass Validate.
+func Migrate(oldConfig string) (string, error) {
+	doc := map[string]any{}
+
+	err := json.Unmarshal([]byte(oldConfig), &doc)
+	if err != nil {
+		return "", fmt.Errorf("encountered error while parsing config: %v", err)
+	}
+
+	legacy, _ := doc["headers"].(string)
+	if legacy == "" {
+		return oldConfig, nil
+	}
+
+	lines := []string{}
+	if existing, _ := doc["request_headers"].(string); existing != "" {
+		lines = append(lines, existing)
+	}
+
+	cookies := []string{}
+	if existing, _ := doc["cookies"].(string); existing != "" {
+		cookies = append(cookies, existing)
+	}
+
+	for _, raw := range strings.Split(legacy, ";") {
+		name, value, ok := strings.Cut(raw, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return "", fmt.Errorf("header format must be \"header:value;header:value\" ; got: %v", legacy)
+		}
+
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+
+		if http.CanonicalHeaderKey(name) == "Cookie" {
+			cookies = append(cookies, value)
+			continue
+		}
+
+		lines = append(lines, name+": "+value)
+	}
+
+	doc["headers"] = ""
+	if len(lines) > 0 {
+		doc["request_headers"] = strings.Join(lines, "\n")
+	}
+	if len(cookies) > 0 {
+		doc["cookies"] = strings.Join(cookies, "; ")
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("encountered error while encoding config: %v", err)
+	}
+
+	err = Validate(string(out))
+	if err != nil {
+		return "", fmt.Errorf("migrated config is invalid: %v", err)
+	}
+
+	return string(out), nil
+}
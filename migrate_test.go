@@ -0,0 +1,65 @@
+package http
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name           string
+		headers        string
+		requestHeaders string
+		cookies        string
+	}{
+		{"plain headers", "X-One:1;X-Two: two", "X-One: 1\nX-Two: two", ""},
+		{"cookie header", "Cookie:a=1;X-One:1", "X-One: 1", "a=1"},
+		{"value with colon", "X-Time:12:30", "X-Time: 12:30", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := map[string]any{}
+			_ = json.Unmarshal([]byte(baselineConfig), &doc)
+			doc["headers"] = tt.headers
+			old, _ := json.Marshal(doc)
+
+			migrated, err := Migrate(string(old))
+			if err != nil {
+				t.Fatalf("Migrate() = %v", err)
+			}
+
+			err = Validate(migrated)
+			if err != nil {
+				t.Fatalf("Validate(migrated) = %v", err)
+			}
+
+			got := map[string]any{}
+			_ = json.Unmarshal([]byte(migrated), &got)
+			if got["headers"] != "" {
+				t.Errorf("headers = %q; want empty", got["headers"])
+			}
+			if tt.requestHeaders != "" && got["request_headers"] != tt.requestHeaders {
+				t.Errorf("request_headers = %q; want %q", got["request_headers"], tt.requestHeaders)
+			}
+			if tt.cookies != "" && got["cookies"] != tt.cookies {
+				t.Errorf("cookies = %q; want %q", got["cookies"], tt.cookies)
+			}
+		})
+	}
+}
+
+func TestMigrateUnchanged(t *testing.T) {
+	migrated, err := Migrate(baselineConfig)
+	if err != nil || migrated != baselineConfig {
+		t.Fatalf("Migrate(no headers) = %q, %v; want config unchanged", migrated, err)
+	}
+}
+
+func TestMigrateInvalidResult(t *testing.T) {
+	_, err := Migrate(`{"url": "", "expected_output": "200", "headers": "X-One:1"}`)
+	if err == nil || !strings.Contains(err.Error(), "migrated config is invalid") {
+		t.Fatalf("Migrate() = %v; want an invalid config error", err)
+	}
+}
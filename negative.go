@@ -0,0 +1,108 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// badCredentialAuths are the auth schemes badCredentialsRequest knows how to
+// send wrong credentials for.
+var badCredentialAuths = []string{"none", "basic", "bearer", "jwt"}
+
+// badCredentialsRequest swaps the configured secret for a random wrong one;
+// for jwt auth, the token is signed with a random key. Configs without an
+// auth provider are treated as a login form post.
+func badCredentialsRequest(conf Schema) Schema {
+	token, _ := nonce()
+	wrong := "wrong-" + token
+	conf.AuthPassword = wrong
+	conf.AuthToken = wrong
+
+	switch conf.Auth {
+	case "none":
+		conf = loginRequest(conf)
+	case "jwt":
+		conf.JWTKey = wrongJWTKey(conf.JWTAlgorithm, wrong)
+	}
+
+	return conf
+}
+
+// wrongRSAKey is the PEM RS256 key wrongJWTKey signs with. Generating one
+// takes long enough that it is made once per process, on first use.
+var (
+	wrongRSAOnce sync.Once
+	wrongRSAKey  string
+)
+
+// wrongJWTKey returns a signing key for algorithm that the service cannot
+// know. An RS256 key that fails to generate leaves secret in its place, so
+// minting fails rather than sending a valid token.
+func wrongJWTKey(algorithm string, secret string) string {
+	if algorithm != "RS256" {
+		return secret
+	}
+
+	wrongRSAOnce.Do(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return
+		}
+		wrongRSAKey = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	})
+
+	if wrongRSAKey == "" {
+		return secret
+	}
+
+	return wrongRSAKey
+}
+
+// badCredentialsMatcher expects a request with wrong credentials to be
+// refused, with status 401 or 403 or with the failure marker in
+// expected_output on the page, so disabling authentication cannot pass.
+type badCredentialsMatcher struct{}
+
+func (badCredentialsMatcher) ValidateConfig(conf Schema) error {
+	if !slices.Contains(badCredentialAuths, conf.Auth) {
+		return fmt.Errorf("rejectsBadCredentials cannot send wrong credentials for auth %v; supported: %s", conf.Auth, strings.Join(badCredentialAuths, ", "))
+	}
+
+	if conf.Auth == "none" && conf.ExpectedOutput == "" {
+		return fmt.Errorf("rejectsBadCredentials on a login form requires expected_output, the login failure marker")
+	}
+
+	if conf.Auth == "none" && (conf.UsernameField == "" || conf.PasswordField == "") {
+		return fmt.Errorf("username_field and password_field must be provided")
+	}
+
+	return nil
+}
+
+func (badCredentialsMatcher) Match(ctx context.Context, ex *Exchange) error {
+	status := ex.Response.StatusCode
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return nil
+	}
+
+	if ex.Config.ExpectedOutput != "" {
+		body, err := readBody(ex)
+		if err != nil {
+			return err
+		}
+
+		if strings.Contains(string(body), ex.Config.ExpectedOutput) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("wrong credentials were not rejected; got status %d without the failure marker", status)
+}
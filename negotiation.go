@@ -0,0 +1,52 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// acceptable reports whether media satisfies a media range of the Accept
+// header accept. Ranges with q=0 refuse rather than accept.
+func acceptable(media string, accept string) bool {
+	for _, item := range splitList(accept) {
+		rangeType, params, _ := strings.Cut(item, ";")
+		rangeType = strings.ToLower(strings.TrimSpace(rangeType))
+
+		refused := false
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "q") && strings.Trim(strings.TrimSpace(value), "0.") == "" {
+				refused = true
+			}
+		}
+		if refused {
+			continue
+		}
+
+		if rangeType == "*/*" || rangeType == media {
+			return true
+		}
+
+		if major, ok := strings.CutSuffix(rangeType, "/*"); ok && strings.HasPrefix(media, major+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// assertNegotiated expects the response Content-Type to be one the accept
+// field asked for.
+func assertNegotiated(ctx context.Context, ex *Exchange) error {
+	if !ex.Config.AcceptNegotiated || ex.Config.Accept == "" {
+		return nil
+	}
+
+	header := ex.Response.Header.Get("Content-Type")
+	if !acceptable(mediaType(header), ex.Config.Accept) {
+		return fmt.Errorf("response Content-Type %q does not honor Accept %q", header, ex.Config.Accept)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAcceptable(t *testing.T) {
+	tests := []struct {
+		media  string
+		accept string
+		want   bool
+	}{
+		{"application/json", "application/json", true},
+		{"application/json", "text/html, application/json;q=0.9", true},
+		{"application/json", "*/*", true},
+		{"application/json", "application/*", true},
+		{"application/json", "text/*", false},
+		{"application/json", "Application/JSON", true},
+		{"application/json", "application/json;q=0", false},
+		{"application/json", "application/json;q=0.000, */*", true},
+		{"application/json", "application/json; q=0.0", false},
+		{"application/json", "application/json;q=0.5", true},
+		{"application/xml", "application/json", false},
+		{"application/json", "", false},
+		{"application/json", "application/json;level=1;q=0.7", true},
+		{"application/json", "application/json;Q=0, */*;q=0.1", true},
+		{"application/json", "*/*;q=0", false},
+		{"application/json", "application/json;q=0.01", true},
+		{"application/jsonp", "application/json", false},
+		{"text/html", "text/*;q=0, */*", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.media+" "+tt.accept, func(t *testing.T) {
+			if got := acceptable(tt.media, tt.accept); got != tt.want {
+				t.Errorf("acceptable(%q, %q) = %v; want %v", tt.media, tt.accept, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssertNegotiated(t *testing.T) {
+	// The server answers with JSON when asked for it and HTML otherwise.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Write([]byte("{}"))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<p>hi</p>"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{"json", "application/json", ""},
+		{"html", "text/html", ""},
+		{"wildcard", "text/*", ""},
+		{"anything", "*/*", ""},
+		{"json refused", "application/json;q=0, text/html", `response Content-Type "application/json; charset=utf-8" does not honor Accept`},
+		{"xml ignored", "application/xml", `response Content-Type "text/html" does not honor Accept "application/xml"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runAgainst(t, server, map[string]any{"expected_output": "200", "accept": tt.accept, "accept_negotiated": true})
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestNegotiationOptional(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+	}))
+	defer server.Close()
+
+	// Without accept_negotiated, accept is only sent.
+	err := runAgainst(t, server, map[string]any{"expected_output": "200", "accept": "application/xml"})
+	checkError(t, err, "")
+
+	err = runAgainst(t, server, map[string]any{"expected_output": "200", "accept_negotiated": true})
+	checkError(t, err, "accept_negotiated requires accept to be provided")
+}
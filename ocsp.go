@@ -0,0 +1,137 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// ocspResponseData is the start of a basic OCSP response's ResponseData,
+// decoded only to read the full CertID of each single response, which
+// ocsp.Response does not expose. Trailing fields are ignored.
+type ocspResponseData struct {
+	Version     int `asn1:"optional,default:0,explicit,tag:0"`
+	ResponderID asn1.RawValue
+	ProducedAt  time.Time `asn1:"generalized"`
+	Responses   []ocspSingleResponse
+}
+
+type ocspSingleResponse struct {
+	CertID ocspCertID
+}
+
+type ocspCertID struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	NameHash      []byte
+	IssuerKeyHash []byte
+	SerialNumber  *big.Int
+}
+
+// ocspStapledMatcher expects the TLS handshake to carry a stapled OCSP
+// response that is signed by the issuer (or its delegated responder), covers
+// the leaf certificate, reports it good, and is currently valid.
+type ocspStapledMatcher struct{}
+
+func (ocspStapledMatcher) ValidateConfig(conf Schema) error {
+	if conf.Insecure {
+		return fmt.Errorf("ocspStapled requires insecure to be false, since the issuer that signs the response must be verified")
+	}
+
+	return nil
+}
+
+func (ocspStapledMatcher) Match(ctx context.Context, ex *Exchange) error {
+	state := ex.Response.TLS
+	if state == nil {
+		return fmt.Errorf("ocspStapled requires an https url")
+	}
+
+	if len(state.OCSPResponse) == 0 {
+		return fmt.Errorf("server did not staple an OCSP response")
+	}
+
+	// Only a verified chain says which issuer the response must be signed
+	// by; the peer could send any certificate as its issuer.
+	if len(state.VerifiedChains) == 0 {
+		return fmt.Errorf("ocspStapled requires a verified certificate chain; the chain was not verified")
+	}
+
+	chain := state.VerifiedChains[0]
+	if len(chain) < 2 {
+		return fmt.Errorf("cannot check stapled OCSP response without the issuing certificate")
+	}
+
+	return checkOCSP(state.OCSPResponse, chain[0], chain[1], time.Now())
+}
+
+func checkOCSP(raw []byte, leaf *x509.Certificate, issuer *x509.Certificate, now time.Time) error {
+	resp, err := ocsp.ParseResponseForCert(raw, leaf, issuer)
+	if err != nil {
+		return fmt.Errorf("stapled OCSP response is invalid: %v", err)
+	}
+
+	err = checkCertID(resp, leaf, issuer)
+	if err != nil {
+		return err
+	}
+
+	switch resp.Status {
+	case ocsp.Good:
+	case ocsp.Unknown:
+		return fmt.Errorf("stapled OCSP response reports the certificate status as unknown")
+	default:
+		return fmt.Errorf("stapled OCSP response reports the certificate revoked at %s", resp.RevokedAt.UTC().Format(time.RFC3339))
+	}
+
+	if now.Before(resp.ThisUpdate) {
+		return fmt.Errorf("stapled OCSP response is not valid until %s", resp.ThisUpdate.UTC().Format(time.RFC3339))
+	}
+
+	if !resp.NextUpdate.IsZero() && now.After(resp.NextUpdate) {
+		return fmt.Errorf("stapled OCSP response expired at %s", resp.NextUpdate.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// checkCertID expects the single response ParseResponseForCert picked by
+// serial number to name issuer as well, by the hashes of its name and key.
+func checkCertID(resp *ocsp.Response, leaf *x509.Certificate, issuer *x509.Certificate) error {
+	der, err := ocsp.CreateRequest(leaf, issuer, &ocsp.RequestOptions{Hash: resp.IssuerHash})
+	if err != nil {
+		return fmt.Errorf("encountered error while computing the OCSP certificate ID: %v", err)
+	}
+
+	want, err := ocsp.ParseRequest(der)
+	if err != nil {
+		return fmt.Errorf("encountered error while computing the OCSP certificate ID: %v", err)
+	}
+
+	data := ocspResponseData{}
+	_, err = asn1.Unmarshal(resp.TBSResponseData, &data)
+	if err != nil {
+		return fmt.Errorf("stapled OCSP response is malformed: %v", err)
+	}
+
+	for _, single := range data.Responses {
+		id := single.CertID
+		if id.SerialNumber == nil || id.SerialNumber.Cmp(leaf.SerialNumber) != 0 {
+			continue
+		}
+
+		if !bytes.Equal(id.NameHash, want.IssuerNameHash) || !bytes.Equal(id.IssuerKeyHash, want.IssuerKeyHash) {
+			return fmt.Errorf("stapled OCSP response covers serial %s from a different issuer", leaf.SerialNumber)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("stapled OCSP response does not cover the server certificate")
+}
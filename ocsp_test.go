@@ -0,0 +1,124 @@
+package http
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+func newTestCert(t *testing.T, name string, serial int64, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() = %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+
+	signer, signerKey := template, crypto.Signer(key)
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, key.Public(), signerKey)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate() = %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate() = %v", err)
+	}
+
+	return &testCert{cert: cert, key: key}
+}
+
+func TestCheckOCSP(t *testing.T) {
+	now := time.Now()
+	ca := newTestCert(t, "Competition CA", 1, nil)
+	other := newTestCert(t, "Other CA", 1, nil)
+	leaf := newTestCert(t, "www.example.com", 42, ca)
+
+	respond := func(issuer *x509.Certificate, responder *testCert, template ocsp.Response) []byte {
+		t.Helper()
+		raw, err := ocsp.CreateResponse(issuer, responder.cert, template, responder.key)
+		if err != nil {
+			t.Fatalf("ocsp.CreateResponse() = %v", err)
+		}
+		return raw
+	}
+
+	good := ocsp.Response{Status: ocsp.Good, SerialNumber: leaf.cert.SerialNumber, ThisUpdate: now.Add(-time.Minute), NextUpdate: now.Add(time.Hour)}
+	with := func(change func(r *ocsp.Response)) ocsp.Response {
+		r := good
+		change(&r)
+		return r
+	}
+
+	tests := []struct {
+		name string
+		raw  []byte
+		want string
+	}{
+		{"good", respond(ca.cert, ca, good), ""},
+		{"revoked", respond(ca.cert, ca, with(func(r *ocsp.Response) {
+			r.Status = ocsp.Revoked
+			r.RevokedAt = now.Add(-time.Hour)
+		})), "revoked at"},
+		{"unknown", respond(ca.cert, ca, with(func(r *ocsp.Response) { r.Status = ocsp.Unknown })), "status as unknown"},
+		{"expired", respond(ca.cert, ca, with(func(r *ocsp.Response) {
+			r.ThisUpdate = now.Add(-2 * time.Hour)
+			r.NextUpdate = now.Add(-time.Hour)
+		})), "expired at"},
+		{"not yet valid", respond(ca.cert, ca, with(func(r *ocsp.Response) { r.ThisUpdate = now.Add(time.Hour) })), "not valid until"},
+		{"other serial", respond(ca.cert, ca, with(func(r *ocsp.Response) { r.SerialNumber = big.NewInt(7) })), "no response matching"},
+		{"signed by another CA", respond(ca.cert, other, good), "bad OCSP signature"},
+		{"names another issuer", respond(other.cert, ca, good), "from a different issuer"},
+		{"malformed", []byte("not ocsp"), "stapled OCSP response is invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkOCSP(tt.raw, leaf.cert, ca.cert, now)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("checkOCSP() = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("checkOCSP() = %v; want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateOCSPRequiresVerification(t *testing.T) {
+	err := Validate(`{"url": "https://example.com/", "match_type": "ocspStapled", "insecure": true}`)
+	if err == nil || !strings.Contains(err.Error(), "ocspStapled requires insecure to be false") {
+		t.Fatalf("Validate() = %v; want insecure error", err)
+	}
+}
@@ -0,0 +1,175 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// linkRel returns the target of the first RFC 8288 Link header entry with
+// relation rel, such as <https://api.example/items?page=2>; rel="next".
+func linkRel(header http.Header, rel string) (string, bool) {
+	for _, value := range header.Values("Link") {
+		for value != "" {
+			start := strings.Index(value, "<")
+			end := strings.Index(value, ">")
+			if start < 0 || end < start {
+				break
+			}
+			target := value[start+1 : end]
+			value = value[end+1:]
+
+			params := value
+			if next := strings.Index(value, ","); next >= 0 {
+				params, value = value[:next], value[next+1:]
+			} else {
+				value = ""
+			}
+
+			for _, param := range strings.Split(params, ";") {
+				name, values, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+					continue
+				}
+				for _, each := range strings.Fields(strings.Trim(strings.TrimSpace(values), `"`)) {
+					if strings.EqualFold(each, rel) {
+						return target, true
+					}
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// paginationMatcher follows Link rel="next" headers from the response, up to
+// max_pages pages in all, and expects the chain to end there. When
+// expected_output is set, the items across all pages, counted from the array
+// at page_items_path, must total it.
+type paginationMatcher struct{}
+
+func (paginationMatcher) ValidateConfig(conf Schema) error {
+	if conf.MaxPages < 1 {
+		return fmt.Errorf("max_pages must be at least 1; got: %d", conf.MaxPages)
+	}
+
+	if conf.PageItemsPath != "" {
+		_, err := parsePath(conf.PageItemsPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if conf.ExpectedOutput != "" {
+		total, err := strconv.Atoi(conf.ExpectedOutput)
+		if err != nil || total < 0 {
+			return fmt.Errorf("expected_output must be a total item count; got: %v", conf.ExpectedOutput)
+		}
+	}
+
+	return nil
+}
+
+func (paginationMatcher) Match(ctx context.Context, ex *Exchange) error {
+	conf := ex.Config
+	counting := conf.ExpectedOutput != ""
+
+	resp := ex.Response
+	page := resp.Request.URL
+	visited := map[string]bool{page.String(): true}
+	total := 0
+
+	for pages := 1; ; pages++ {
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("expected 2xx status code for page %d (%s); got: %d", pages, page, resp.StatusCode)
+		}
+
+		if counting {
+			var body []byte
+			var err error
+			if pages == 1 {
+				body, err = readBody(ex)
+			} else {
+				body, err = io.ReadAll(resp.Body)
+				if err != nil {
+					err = fmt.Errorf("encountered error while reading response body: %v", err)
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			count, err := pageItems(body, conf.PageItemsPath)
+			if err != nil {
+				return fmt.Errorf("page %d (%s): %v", pages, page, err)
+			}
+			total += count
+		}
+
+		next, ok := linkRel(resp.Header, "next")
+		if !ok {
+			break
+		}
+
+		ref, err := page.Parse(next)
+		if err != nil {
+			return fmt.Errorf("page %d has an invalid next link: %v; %q", pages, next, err)
+		}
+
+		if visited[ref.String()] {
+			return fmt.Errorf("pagination loops back to %s after %d pages", ref, pages)
+		}
+		visited[ref.String()] = true
+
+		if pages == conf.MaxPages {
+			return fmt.Errorf("pagination did not end within max_pages (%d); page %d links to %s", conf.MaxPages, pages, ref)
+		}
+
+		resp, err = ex.fetch(ctx, ref.String())
+		if err != nil {
+			return err
+		}
+		defer drainBody(resp.Body)
+		page = resp.Request.URL
+	}
+
+	if counting {
+		want, err := strconv.Atoi(conf.ExpectedOutput)
+		if err != nil {
+			return fmt.Errorf("expected_output must be a total item count; got: %v", conf.ExpectedOutput)
+		}
+
+		if total != want {
+			return fmt.Errorf("expected %d items across %d pages; got: %d", want, len(visited), total)
+		}
+	}
+
+	return nil
+}
+
+// pageItems counts the elements of the JSON array at path in body, or of the
+// body itself when path is empty.
+func pageItems(body []byte, path string) (int, error) {
+	doc, err := decodeJSON(body)
+	if err != nil {
+		return 0, fmt.Errorf("response body is not valid json: %v", err)
+	}
+
+	if path != "" {
+		doc, err = lookupPath(doc, path)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	items, ok := doc.([]any)
+	if !ok {
+		return 0, fmt.Errorf("expected a JSON array of items; got: %s", jsonType(doc))
+	}
+
+	return len(items), nil
+}
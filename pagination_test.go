@@ -0,0 +1,105 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestLinkRel(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		rel    string
+		want   string
+		ok     bool
+	}{
+		{"single", []string{`<https://api.example/items?page=2>; rel="next"`}, "next", "https://api.example/items?page=2", true},
+		{"unquoted", []string{`</items?page=2>; rel=next`}, "next", "/items?page=2", true},
+		{"second entry", []string{`</items?page=1>; rel="prev", </items?page=3>; rel="next"`}, "next", "/items?page=3", true},
+		{"second field", []string{`</items?page=1>; rel="prev"`, `</items?page=3>; rel="next"`}, "next", "/items?page=3", true},
+		{"several relations", []string{`</items?page=9>; rel="last next"`}, "next", "/items?page=9", true},
+		{"case insensitive", []string{`</items?page=2>; REL="Next"`}, "next", "/items?page=2", true},
+		{"other params", []string{`</items?page=2>; title="more"; rel="next"`}, "next", "/items?page=2", true},
+		{"no match", []string{`</items?page=1>; rel="prev"`}, "next", "", false},
+		{"relation prefix", []string{`</items?page=2>; rel="nextpage"`}, "next", "", false},
+		{"malformed", []string{`https://api.example/; rel="next"`}, "next", "", false},
+		{"none", nil, "next", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for _, value := range tt.values {
+				header.Add("Link", value)
+			}
+
+			got, ok := linkRel(header, tt.rel)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("linkRel(%q, %q) = %q, %v; want %q, %v", tt.values, tt.rel, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+// pagesServer serves /items?page=N for pages 1 through 3, two items each,
+// linking each page to the next. /loop links page 2 back to page 1 and
+// /broken fails on page 2.
+func pagesServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		page = max(page, 1)
+
+		switch {
+		case r.URL.Path == "/broken" && page == 2:
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		case r.URL.Path == "/loop" && page == 2:
+			w.Header().Set("Link", `<?page=1>; rel="next"`)
+		case page < 3:
+			w.Header().Set("Link", fmt.Sprintf(`<?page=%d>; rel="next"`, page+1))
+		}
+
+		fmt.Fprintf(w, `{"data": [%d, %d]}`, page*2-1, page*2)
+	}))
+}
+
+func TestPagination(t *testing.T) {
+	server := pagesServer()
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		conf map[string]any
+		want string
+	}{
+		{"ends", map[string]any{}, ""},
+		{"counted", map[string]any{"expected_output": "6"}, ""},
+		{"miscounted", map[string]any{"expected_output": "5"}, "expected 5 items across 3 pages; got: 6"},
+		{"exactly max pages", map[string]any{"max_pages": 3}, ""},
+		{"too many pages", map[string]any{"max_pages": 2}, "pagination did not end within max_pages (2); page 2 links to"},
+		{"loop", map[string]any{"url": server.URL + "/loop"}, "pagination loops back to"},
+		{"page fails", map[string]any{"url": server.URL + "/broken"}, "expected 2xx status code for page 2"},
+		{"not an array", map[string]any{"expected_output": "6", "page_items_path": ""}, "page 1 (" + server.URL + "/items): expected a JSON array of items; got: object"},
+		{"no pages", map[string]any{"max_pages": 0}, "max_pages must be at least 1; got: 0"},
+		{"bad count", map[string]any{"expected_output": "-1"}, "expected_output must be a total item count; got: -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := map[string]any{
+				"url":             server.URL + "/items",
+				"match_type":      "pagination",
+				"page_items_path": "data",
+			}
+			for key, value := range tt.conf {
+				conf[key] = value
+			}
+
+			err := runAgainst(t, server, conf)
+			checkError(t, err, tt.want)
+		})
+	}
+}
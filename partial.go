@@ -0,0 +1,140 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// partialContentMatcher expects a 206 whose Content-Range and body agree with
+// the range that was requested.
+type partialContentMatcher struct{}
+
+func (partialContentMatcher) ValidateConfig(conf Schema) error {
+	if conf.Range == "" {
+		return fmt.Errorf("partialContent requires range to be provided")
+	}
+
+	return nil
+}
+
+func (partialContentMatcher) Match(ctx context.Context, ex *Exchange) error {
+	if ex.Response.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("expected status code: %d; got: %d", http.StatusPartialContent, ex.Response.StatusCode)
+	}
+
+	first, last, err := parseByteRange(ex.Config.Range)
+	if err != nil {
+		return err
+	}
+
+	header := ex.Response.Header.Get("Content-Range")
+	start, end, total, err := parseContentRange(header)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case first >= 0 && start != first:
+		return fmt.Errorf("expected Content-Range to start at %d; got: %q", first, header)
+	case first >= 0 && last >= 0 && end > last:
+		return fmt.Errorf("expected Content-Range to end by %d; got: %q", last, header)
+	case first < 0 && end-start+1 > -first:
+		return fmt.Errorf("expected Content-Range to cover at most the last %d bytes; got: %q", -first, header)
+	case first < 0 && total >= 0 && end != total-1:
+		return fmt.Errorf("expected Content-Range to reach the end of the resource; got: %q", header)
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	if int64(len(body)) != end-start+1 {
+		return fmt.Errorf("expected %d body bytes for Content-Range %q; got: %d", end-start+1, header, len(body))
+	}
+
+	return nil
+}
+
+// parseByteRange parses a single-range Range header value. It returns
+// first=-n for a suffix range "bytes=-n" and last=-1 for an open range
+// "bytes=n-".
+func parseByteRange(value string) (int64, int64, error) {
+	spec, ok := strings.CutPrefix(value, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, 0, fmt.Errorf("range must be a single byte range like \"bytes=0-99\"; got: %v", value)
+	}
+
+	from, to, ok := strings.Cut(spec, "-")
+	if !ok || (from == "" && to == "") {
+		return 0, 0, fmt.Errorf("range must be a single byte range like \"bytes=0-99\"; got: %v", value)
+	}
+
+	if from == "" {
+		n, err := strconv.ParseInt(to, 10, 64)
+		if err != nil || n <= 0 {
+			return 0, 0, fmt.Errorf("invalid suffix length in range: %v", value)
+		}
+		return -n, -1, nil
+	}
+
+	first, err := strconv.ParseInt(from, 10, 64)
+	if err != nil || first < 0 {
+		return 0, 0, fmt.Errorf("invalid first byte in range: %v", value)
+	}
+
+	if to == "" {
+		return first, -1, nil
+	}
+
+	last, err := strconv.ParseInt(to, 10, 64)
+	if err != nil || last < first {
+		return 0, 0, fmt.Errorf("invalid last byte in range: %v", value)
+	}
+
+	return first, last, nil
+}
+
+// parseContentRange parses "bytes start-end/total", returning total=-1 when
+// the complete length is "*".
+func parseContentRange(value string) (int64, int64, int64, error) {
+	invalid := fmt.Errorf("invalid Content-Range header: %q", value)
+
+	spec, ok := strings.CutPrefix(value, "bytes ")
+	if !ok {
+		return 0, 0, 0, invalid
+	}
+
+	span, size, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, 0, invalid
+	}
+
+	from, to, ok := strings.Cut(span, "-")
+	if !ok {
+		return 0, 0, 0, invalid
+	}
+
+	start, err := strconv.ParseInt(from, 10, 64)
+	if err != nil {
+		return 0, 0, 0, invalid
+	}
+
+	end, err := strconv.ParseInt(to, 10, 64)
+	if err != nil || end < start {
+		return 0, 0, 0, invalid
+	}
+
+	total := int64(-1)
+	if size != "*" {
+		total, err = strconv.ParseInt(size, 10, 64)
+		if err != nil || total <= end {
+			return 0, 0, 0, invalid
+		}
+	}
+
+	return start, end, total, nil
+}
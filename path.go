@@ -0,0 +1,161 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pathStep is one segment of a value path: an object key or an array index.
+type pathStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parsePath parses paths like "spec.containers[0].image" or "[2].name".
+// Keys containing dots or brackets are not supported.
+func parsePath(raw string) ([]pathStep, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, fmt.Errorf("value_path must be provided")
+	}
+
+	steps := []pathStep{}
+	for _, part := range strings.Split(raw, ".") {
+		key, rest, _ := strings.Cut(part, "[")
+		if key != "" {
+			steps = append(steps, pathStep{key: key})
+		} else if rest == "" {
+			return nil, fmt.Errorf("empty segment in value_path: %q", raw)
+		}
+
+		for rest != "" {
+			number, after, ok := strings.Cut(rest, "]")
+			if !ok {
+				return nil, fmt.Errorf("unterminated index in value_path: %q", raw)
+			}
+
+			index, err := strconv.Atoi(number)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid index %q in value_path: %q", number, raw)
+			}
+			steps = append(steps, pathStep{index: index, isIndex: true})
+
+			if after == "" {
+				break
+			}
+			if !strings.HasPrefix(after, "[") {
+				return nil, fmt.Errorf("unexpected %q in value_path: %q", after, raw)
+			}
+			rest = after[1:]
+		}
+	}
+
+	return steps, nil
+}
+
+// lookupPath walks a decoded JSON or YAML document along path.
+func lookupPath(doc any, path string) (any, error) {
+	steps, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := doc
+	for i, step := range steps {
+		if step.isIndex {
+			list, ok := current.([]any)
+			if !ok || step.index >= len(list) {
+				return nil, fmt.Errorf("value_path %q not found: no element %d at step %d", path, step.index, i+1)
+			}
+			current = list[step.index]
+			continue
+		}
+
+		var ok bool
+		switch object := current.(type) {
+		case map[string]any:
+			current, ok = object[step.key]
+		case map[any]any:
+			current, ok = lookupKey(object, step.key)
+		}
+		if !ok {
+			return nil, fmt.Errorf("value_path %q not found: no key %q at step %d", path, step.key, i+1)
+		}
+	}
+
+	return current, nil
+}
+
+// lookupKey finds key in a YAML mapping, whose keys need not be strings:
+// key 1 is found by the path segment "1".
+func lookupKey(object map[any]any, key string) (any, bool) {
+	if value, ok := object[key]; ok {
+		return value, true
+	}
+
+	for k, value := range object {
+		if _, isString := k.(string); !isString && scalarString(k) == key {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+// scalarString renders a looked-up value for comparison with expected_output.
+// Numbers are written out in full, never in exponent form, and objects and
+// arrays render as compact JSON.
+func scalarString(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case time.Time:
+		if value.Equal(value.Truncate(24 * time.Hour)) {
+			return value.Format(time.DateOnly)
+		}
+		return value.Format(time.RFC3339Nano)
+	case map[string]any, map[any]any, []any:
+		out, err := json.Marshal(jsonValue(value))
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(out)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// jsonValue converts the YAML mappings in value to objects json can encode.
+func jsonValue(value any) any {
+	switch value := value.(type) {
+	case map[any]any:
+		object := make(map[string]any, len(value))
+		for k, v := range value {
+			object[scalarString(k)] = jsonValue(v)
+		}
+		return object
+	case map[string]any:
+		object := make(map[string]any, len(value))
+		for k, v := range value {
+			object[k] = jsonValue(v)
+		}
+		return object
+	case []any:
+		list := make([]any, len(value))
+		for i, v := range value {
+			list[i] = jsonValue(v)
+		}
+		return list
+	default:
+		return value
+	}
+}
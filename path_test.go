@@ -0,0 +1,98 @@
+package http
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []pathStep
+		err  string
+	}{
+		{"name", []pathStep{{key: "name"}}, ""},
+		{"spec.containers[0].image", []pathStep{{key: "spec"}, {key: "containers"}, {index: 0, isIndex: true}, {key: "image"}}, ""},
+		{"[2].name", []pathStep{{index: 2, isIndex: true}, {key: "name"}}, ""},
+		{"matrix[1][3]", []pathStep{{key: "matrix"}, {index: 1, isIndex: true}, {index: 3, isIndex: true}}, ""},
+		{"", nil, "value_path must be provided"},
+		{"a..b", nil, "empty segment"},
+		{"items[0", nil, "unterminated index"},
+		{"items[-1]", nil, `invalid index "-1"`},
+		{"items[x]", nil, `invalid index "x"`},
+		{"items[0]x", nil, `unexpected "x"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parsePath(tt.raw)
+			checkError(t, err, tt.err)
+			if tt.err == "" && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePath(%q) = %+v; want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupPath(t *testing.T) {
+	doc, err := decodeJSON([]byte(`{"spec": {"containers": [{"image": "nginx:1.26", "ports": [80, 443]}]}, "ok": true, "none": null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+		err  string
+	}{
+		{"spec.containers[0].image", "nginx:1.26", ""},
+		{"spec.containers[0].ports[1]", "443", ""},
+		{"spec.containers[0].ports", "[80,443]", ""},
+		{"ok", "true", ""},
+		{"none", "null", ""},
+		{"spec.containers[1]", "", "no element 1 at step 3"},
+		{"spec.volumes", "", `no key "volumes" at step 2`},
+		{"ok.value", "", `no key "value" at step 2`},
+		{"spec[0]", "", "no element 0 at step 2"},
+		{"ok[0]", "", "no element 0 at step 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			value, err := lookupPath(doc, tt.path)
+			checkError(t, err, tt.err)
+			if tt.err == "" && scalarString(value) != tt.want {
+				t.Errorf("lookupPath(%q) = %s; want %s", tt.path, scalarString(value), tt.want)
+			}
+		})
+	}
+}
+
+func TestScalarString(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"null", nil, "null"},
+		{"json number", json.Number("1e6"), "1e6"},
+		{"large float", 1e6, "1000000"},
+		{"small float", 0.000001, "0.000001"},
+		{"int", 42, "42"},
+		{"uint", uint64(12345678901234567890), "12345678901234567890"},
+		{"bool", true, "true"},
+		{"date", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), "2024-01-02"},
+		{"timestamp", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "2024-01-02T03:04:05Z"},
+		{"yaml mapping", map[any]any{1: "one", "b": []any{map[any]any{true: 2.5}}}, `{"1":"one","b":[{"true":2.5}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scalarString(tt.value); got != tt.want {
+				t.Errorf("scalarString(%#v) = %q; want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
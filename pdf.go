@@ -0,0 +1,256 @@
+package http
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// maxPDFStream bounds how much a single content stream may inflate to.
+const maxPDFStream = 16 << 20
+
+// pdfMatcher expects the body to be a complete PDF and, when expected_output
+// is set, its text to contain it. Whitespace is ignored when comparing text,
+// since PDFs position words rather than spacing them.
+type pdfMatcher struct{}
+
+func (pdfMatcher) ValidateConfig(conf Schema) error {
+	return nil
+}
+
+func (pdfMatcher) Match(ctx context.Context, ex *Exchange) error {
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	head := body[:min(len(body), 1024)]
+	if !bytes.Contains(head, []byte("%PDF-")) {
+		return fmt.Errorf("response body is not a pdf: missing %%PDF- header")
+	}
+
+	tail := body[max(0, len(body)-1024):]
+	if !bytes.Contains(tail, []byte("%%EOF")) {
+		return fmt.Errorf("response body is not a complete pdf: missing %%%%EOF trailer")
+	}
+
+	if ex.Config.ExpectedOutput == "" {
+		return nil
+	}
+
+	text := stripSpace(pdfText(body))
+	if !strings.Contains(text, stripSpace(ex.Config.ExpectedOutput)) {
+		return fmt.Errorf("expected output not found in pdf text")
+	}
+
+	return nil
+}
+
+func stripSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// pdfText extracts the literal and hex strings shown by text operators in
+// every content stream it can read: unfiltered or FlateDecode. It makes no
+// attempt at font encodings, which is enough for documents with plain text.
+func pdfText(body []byte) string {
+	var text strings.Builder
+
+	rest := body
+	for {
+		start := bytes.Index(rest, []byte("stream"))
+		if start < 0 {
+			break
+		}
+
+		// The stream's dictionary sits between its "obj" and "stream".
+		dict := rest[:start]
+		if obj := bytes.LastIndex(dict, []byte("obj")); obj >= 0 {
+			dict = dict[obj:]
+		}
+
+		data := rest[start+len("stream"):]
+		data = bytes.TrimPrefix(data, []byte("\r"))
+		data = bytes.TrimPrefix(data, []byte("\n"))
+
+		end := bytes.Index(data, []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		rest = data[end+len("endstream"):]
+		data = data[:end]
+
+		if bytes.Contains(dict, []byte("/FlateDecode")) {
+			reader, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				continue
+			}
+			// Keep whatever inflated before any error; stream lengths are
+			// often padded.
+			data, _ = io.ReadAll(io.LimitReader(reader, maxPDFStream))
+		} else if bytes.Contains(dict, []byte("/Filter")) {
+			continue
+		}
+
+		for _, s := range pdfStrings(data) {
+			text.WriteString(s)
+			text.WriteByte(' ')
+		}
+	}
+
+	return text.String()
+}
+
+// pdfStrings returns the literal (parenthesised) and hex (<...>) strings
+// between BT and ET text operators in a content stream.
+func pdfStrings(content []byte) []string {
+	var strs []string
+
+	inText := false
+	for i := 0; i < len(content); i++ {
+		switch {
+		case !inText && bytes.HasPrefix(content[i:], []byte("BT")):
+			inText = true
+			i++
+		case inText && bytes.HasPrefix(content[i:], []byte("ET")):
+			inText = false
+			i++
+		case inText && content[i] == '(':
+			s, n := pdfLiteral(content[i:])
+			strs = append(strs, s)
+			i += n - 1
+		case inText && bytes.HasPrefix(content[i:], []byte("<<")):
+			// A dictionary operand, such as marked-content properties.
+			end := bytes.Index(content[i:], []byte(">>"))
+			if end < 0 {
+				return strs
+			}
+			i += end + 1
+		case inText && content[i] == '<':
+			s, n := pdfHex(content[i:])
+			strs = append(strs, s)
+			i += n - 1
+		}
+	}
+
+	return strs
+}
+
+// pdfLiteral decodes the literal string at the start of b, returning it and
+// the number of bytes consumed.
+func pdfLiteral(b []byte) (string, int) {
+	var s strings.Builder
+
+	depth := 0
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch c {
+		case '(':
+			depth++
+			if depth == 1 {
+				continue
+			}
+		case ')':
+			depth--
+			if depth == 0 {
+				return s.String(), i + 1
+			}
+		case '\\':
+			if i+1 >= len(b) {
+				return s.String(), len(b)
+			}
+			i++
+			switch e := b[i]; e {
+			case 'n':
+				s.WriteByte('\n')
+			case 'r':
+				s.WriteByte('\r')
+			case 't':
+				s.WriteByte('\t')
+			case 'b':
+				s.WriteByte('\b')
+			case 'f':
+				s.WriteByte('\f')
+			case '\r', '\n':
+				// Line continuation.
+				if e == '\r' && i+1 < len(b) && b[i+1] == '\n' {
+					i++
+				}
+			default:
+				if e >= '0' && e <= '7' {
+					code := 0
+					for n := 0; n < 3 && i < len(b) && b[i] >= '0' && b[i] <= '7'; n++ {
+						code = code*8 + int(b[i]-'0')
+						i++
+					}
+					i--
+					s.WriteByte(byte(code))
+				} else {
+					s.WriteByte(e)
+				}
+			}
+			continue
+		}
+		s.WriteByte(c)
+	}
+
+	return s.String(), len(b)
+}
+
+// pdfHex decodes the hex string at the start of b, returning it and the
+// number of bytes consumed. Whitespace is ignored and a final odd digit is
+// padded with 0. Strings with a UTF-16BE byte order mark are decoded as such.
+func pdfHex(b []byte) (string, int) {
+	var raw []byte
+
+	end := bytes.IndexByte(b, '>')
+	if end < 0 {
+		end = len(b)
+	}
+
+	half, odd := byte(0), false
+	for _, c := range b[1:end] {
+		var v byte
+		switch {
+		case c >= '0' && c <= '9':
+			v = c - '0'
+		case c >= 'a' && c <= 'f':
+			v = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			v = c - 'A' + 10
+		default:
+			continue
+		}
+
+		if odd {
+			raw = append(raw, half<<4|v)
+		} else {
+			half = v
+		}
+		odd = !odd
+	}
+	if odd {
+		raw = append(raw, half<<4)
+	}
+
+	consumed := min(end+1, len(b))
+	if len(raw) >= 2 && raw[0] == 0xFE && raw[1] == 0xFF {
+		units := make([]uint16, 0, len(raw)/2)
+		for i := 2; i+1 < len(raw); i += 2 {
+			units = append(units, uint16(raw[i])<<8|uint16(raw[i+1]))
+		}
+		return string(utf16.Decode(units)), consumed
+	}
+
+	return string(raw), consumed
+}
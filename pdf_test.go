@@ -0,0 +1,86 @@
+package http
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// testPDF wraps content in a one-stream PDF, deflated when compress is set.
+func testPDF(content string, compress bool) []byte {
+	data, dict := []byte(content), "<< /Length %d >>"
+	if compress {
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		w.Write(data)
+		w.Close()
+		data, dict = buf.Bytes(), "<< /Length %d /Filter /FlateDecode >>"
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.7\n4 0 obj\n")
+	fmt.Fprintf(&pdf, dict, len(data))
+	pdf.WriteString("\nstream\n")
+	pdf.Write(data)
+	pdf.WriteString("\nendstream\nendobj\ntrailer\n<< /Root 1 0 R >>\n%%EOF\n")
+
+	return pdf.Bytes()
+}
+
+func TestPDFStrings(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"literal", `BT /F1 12 Tf (Hello World) Tj ET`, []string{"Hello World"}},
+		{"escapes", `BT (a\(b\)c\\d\n\101) Tj ET`, []string{"a(b)c\\d\nA"}},
+		{"nested parentheses", `BT (f(x)) Tj ET`, []string{"f(x)"}},
+		{"hex", `BT <48656C6C6F> Tj ET`, []string{"Hello"}},
+		{"hex with spaces and odd digit", `BT <48 65 6c 6c 6f 7> Tj ET`, []string{"Hellop"}},
+		{"hex utf-16", `BT <FEFF00480069263A> Tj ET`, []string{"Hi☺"}},
+		{"TJ array", `BT [(Sc) -20 <6F7265> 10 (board)] TJ ET`, []string{"Sc", "ore", "board"}},
+		{"marked content dictionary", `BT /Span << /ActualText (x) >> BDC <4869> Tj EMC ET`, []string{"Hi"}},
+		{"outside text object", `(ignored) <6E6F> BT (kept) Tj ET`, []string{"kept"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pdfStrings([]byte(tt.content))
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("pdfStrings(%q) = %q; want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPDFMatcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     []byte
+		expected string
+		want     string
+	}{
+		{"literal", testPDF(`BT (Team Blue Scoreboard) Tj ET`, false), "Blue Scoreboard", ""},
+		{"hex deflated", testPDF(`BT [<5465616D20426C7565> -250 <53636F7265626F617264>] TJ ET`, true), "Team Blue Scoreboard", ""},
+		{"missing text", testPDF(`BT (Team Red) Tj ET`, true), "Team Blue", "expected output not found in pdf text"},
+		{"no text required", testPDF(``, false), "", ""},
+		{"not a pdf", []byte("<html></html>"), "", "missing %PDF- header"},
+		{"truncated", testPDF(`BT (x) Tj ET`, false)[:40], "", "missing %%EOF trailer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write(tt.body)
+			}))
+			defer server.Close()
+
+			err := runAgainst(t, server, map[string]any{"match_type": "pdf", "expected_output": tt.expected})
+			checkError(t, err, tt.want)
+		})
+	}
+}
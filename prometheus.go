@@ -0,0 +1,204 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// promSample is one series value from a Prometheus text exposition.
+type promSample struct {
+	name   string
+	labels map[string]string
+	value  float64
+}
+
+// promComparisons are the operators a prometheus expression may use.
+var promComparisons = map[string]func(a, b float64) bool{
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+	"<=": func(a, b float64) bool { return a <= b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	">":  func(a, b float64) bool { return a > b },
+}
+
+// promExpr is a parsed prometheus expected_output such as
+// `up{job="node"} == 1`. Without an operator it only requires the metric.
+type promExpr struct {
+	sample promSample
+	op     string
+	want   float64
+}
+
+func parsePromExpr(raw string) (promExpr, error) {
+	expr := promExpr{}
+
+	selector := strings.TrimSpace(raw)
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		// Operators are only looked for after any label braces.
+		brace := max(strings.LastIndex(selector, "}"), 0)
+		i := strings.Index(selector[brace:], op)
+		if i < 0 {
+			continue
+		}
+		i += brace
+
+		want, err := strconv.ParseFloat(strings.TrimSpace(selector[i+len(op):]), 64)
+		if err != nil {
+			return expr, fmt.Errorf("invalid prometheus comparison value provided: %v", raw)
+		}
+
+		expr.op, expr.want = op, want
+		selector = strings.TrimSpace(selector[:i])
+		break
+	}
+
+	sample, rest, err := parsePromSeries(selector)
+	if err != nil || strings.TrimSpace(rest) != "" || sample.name == "" {
+		return expr, fmt.Errorf("expected_output must be a metric like `up{job=\"node\"} == 1`; got: %v", raw)
+	}
+	expr.sample = sample
+
+	return expr, nil
+}
+
+// parsePromSeries parses a metric name and optional label set from the start
+// of line, returning the remainder.
+func parsePromSeries(line string) (promSample, string, error) {
+	sample := promSample{labels: map[string]string{}}
+
+	end := strings.IndexAny(line, "{ \t")
+	if end < 0 {
+		end = len(line)
+	}
+	sample.name = line[:end]
+	line = line[end:]
+
+	if !strings.HasPrefix(line, "{") {
+		return sample, line, nil
+	}
+	line = line[1:]
+
+	for {
+		line = strings.TrimLeft(line, " \t,")
+		if strings.HasPrefix(line, "}") {
+			return sample, line[1:], nil
+		}
+
+		name, rest, ok := strings.Cut(line, "=")
+		if !ok {
+			return sample, "", fmt.Errorf("malformed label set")
+		}
+		rest = strings.TrimLeft(rest, " \t")
+		if !strings.HasPrefix(rest, `"`) {
+			return sample, "", fmt.Errorf("malformed label value")
+		}
+
+		var value strings.Builder
+		i := 1
+		for ; i < len(rest) && rest[i] != '"'; i++ {
+			if rest[i] == '\\' && i+1 < len(rest) {
+				i++
+				if rest[i] == 'n' {
+					value.WriteByte('\n')
+					continue
+				}
+			}
+			value.WriteByte(rest[i])
+		}
+		if i >= len(rest) {
+			return sample, "", fmt.Errorf("unterminated label value")
+		}
+
+		sample.labels[strings.TrimSpace(name)] = value.String()
+		line = rest[i+1:]
+	}
+}
+
+// parseExposition parses the samples of a Prometheus text format body,
+// skipping comments and lines it cannot read.
+func parseExposition(body string) []promSample {
+	samples := []promSample{}
+
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		sample, rest, err := parsePromSeries(line)
+		if err != nil {
+			continue
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+
+		sample.value, err = strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+
+		samples = append(samples, sample)
+	}
+
+	return samples
+}
+
+func (s promSample) matches(selector promSample) bool {
+	if s.name != selector.name {
+		return false
+	}
+
+	for name, value := range selector.labels {
+		if s.labels[name] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// prometheusMatcher parses a Prometheus text exposition and expects the
+// metric selected by expected_output to exist and, when a comparison is
+// given, every matching series to satisfy it.
+type prometheusMatcher struct{}
+
+func (prometheusMatcher) ValidateConfig(conf Schema) error {
+	_, err := parsePromExpr(conf.ExpectedOutput)
+	return err
+}
+
+func (prometheusMatcher) Match(ctx context.Context, ex *Exchange) error {
+	expr, err := parsePromExpr(ex.Config.ExpectedOutput)
+	if err != nil {
+		return err
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	found := 0
+	for _, sample := range parseExposition(string(body)) {
+		if !sample.matches(expr.sample) {
+			continue
+		}
+		found++
+
+		if expr.op != "" && !promComparisons[expr.op](sample.value, expr.want) {
+			return fmt.Errorf("metric %s%v = %v; expected %s %v", sample.name, sample.labels, sample.value, expr.op, expr.want)
+		}
+	}
+
+	if found == 0 {
+		return fmt.Errorf("metric %s not found in exposition", strings.TrimSpace(ex.Config.ExpectedOutput))
+	}
+
+	return nil
+}
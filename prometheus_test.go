@@ -0,0 +1,91 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParsePromExpr(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want promExpr
+		err  string
+	}{
+		{"up", promExpr{sample: promSample{name: "up", labels: map[string]string{}}}, ""},
+		{`up{job="node"} == 1`, promExpr{sample: promSample{name: "up", labels: map[string]string{"job": "node"}}, op: "==", want: 1}, ""},
+		{`http_requests_total{code="200",path="a>b"} >= 10`, promExpr{sample: promSample{name: "http_requests_total", labels: map[string]string{"code": "200", "path": "a>b"}}, op: ">=", want: 10}, ""},
+		{"queue_depth<5", promExpr{sample: promSample{name: "queue_depth", labels: map[string]string{}}, op: "<", want: 5}, ""},
+		{`up{job="node\"x"} != 0`, promExpr{sample: promSample{name: "up", labels: map[string]string{"job": `node"x`}}, op: "!=", want: 0}, ""},
+		{"up == one", promExpr{}, "invalid prometheus comparison value"},
+		{"", promExpr{}, "expected_output must be a metric"},
+		{`up{job=node}`, promExpr{}, "expected_output must be a metric"},
+		{`up{job="node"`, promExpr{}, "expected_output must be a metric"},
+		{"up extra", promExpr{}, "expected_output must be a metric"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parsePromExpr(tt.raw)
+			checkError(t, err, tt.err)
+			if tt.err == "" && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePromExpr(%q) = %+v; want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExposition(t *testing.T) {
+	body := `# HELP up Whether the target is up.
+# TYPE up gauge
+up{job="node",instance="a"} 1
+up{job="node",instance="b"} 0 1700000000000
+queue_depth 3.5
+latency_bucket{le="+Inf"} 42
+motd{text="a,b=\"c\""} 1
+
+not a sample
+bad_value NaNx
+`
+	want := []promSample{
+		{name: "up", labels: map[string]string{"job": "node", "instance": "a"}, value: 1},
+		{name: "up", labels: map[string]string{"job": "node", "instance": "b"}, value: 0},
+		{name: "queue_depth", labels: map[string]string{}, value: 3.5},
+		{name: "latency_bucket", labels: map[string]string{"le": "+Inf"}, value: 42},
+		{name: "motd", labels: map[string]string{"text": `a,b="c"`}, value: 1},
+	}
+
+	got := parseExposition(body)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseExposition() = %+v; want %+v", got, want)
+	}
+}
+
+func TestPrometheusMatcher(t *testing.T) {
+	body := "up{job=\"node\",instance=\"a\"} 1\nup{job=\"node\",instance=\"b\"} 0\nqueue_depth 3\n"
+
+	tests := []struct {
+		expected string
+		want     string
+	}{
+		{"queue_depth", ""},
+		{"queue_depth < 5", ""},
+		{`up{instance="a"} == 1`, ""},
+		{`up{job="node"} == 1`, `expected == 1`},
+		{"queue_depth > 5", "expected > 5"},
+		{`up{job="db"}`, "not found in exposition"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			err := runAgainst(t, server, map[string]any{"match_type": "prometheus", "expected_output": tt.expected})
+			checkError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// runRateLimit sends a burst of Samples requests at once and asserts whether
+// the service throttled any of them with 429 Too Many Requests.
+func (c *Checker) runRateLimit(ctx context.Context, conf Schema, res *Result) error {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	limited := 0
+	missingRetry := 0
+	var lastErr error
+
+	for i := 0; i < conf.Samples; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ex, err := c.exchange(ctx, conf, res)
+			if err != nil {
+				mu.Lock()
+				lastErr = err
+				mu.Unlock()
+				return
+			}
+			defer drainBody(ex.Response.Body)
+
+			if ex.Response.StatusCode != http.StatusTooManyRequests {
+				return
+			}
+
+			mu.Lock()
+			limited++
+			if !validRetryAfter(ex.Response.Header.Get("Retry-After")) {
+				missingRetry++
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if !conf.ExpectLimited {
+		if limited > 0 {
+			return fmt.Errorf("expected no rate limiting; got %d of %d requests with status %d", limited, conf.Samples, http.StatusTooManyRequests)
+		}
+		if lastErr != nil {
+			return lastErr
+		}
+		return nil
+	}
+
+	if limited == 0 {
+		if lastErr != nil {
+			return fmt.Errorf("expected status %d within %d requests; got none; last error: %v", http.StatusTooManyRequests, conf.Samples, lastErr)
+		}
+		return fmt.Errorf("expected status %d within %d requests; got none", http.StatusTooManyRequests, conf.Samples)
+	}
+
+	if conf.RequireRetry && missingRetry > 0 {
+		return fmt.Errorf("%d of %d rate limited responses had no valid Retry-After header", missingRetry, limited)
+	}
+
+	return nil
+}
+
+// validRetryAfter reports whether value is delay-seconds or an HTTP-date, as
+// allowed by RFC 9110.
+func validRetryAfter(value string) bool {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return seconds >= 0
+	}
+
+	_, err := http.ParseTime(value)
+	return err == nil
+}
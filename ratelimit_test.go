@@ -0,0 +1,80 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestValidRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"120", true},
+		{"0", true},
+		{" 5 ", true},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", true},
+		{"Wednesday, 21-Oct-15 07:28:00 GMT", true},
+		{"", false},
+		{"-1", false},
+		{"1.5", false},
+		{"soon", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := validRetryAfter(tt.value); got != tt.want {
+				t.Errorf("validRetryAfter(%q) = %v; want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	// limiter lets the first allowed requests through and throttles the rest,
+	// sending retryAfter with each 429 when it is set.
+	limiter := func(allowed int32, retryAfter string) http.Handler {
+		var requests atomic.Int32
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) <= allowed {
+				return
+			}
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+	}
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		conf    map[string]any
+		want    string
+	}{
+		{"throttled", limiter(2, "1"), nil, ""},
+		{"never throttled", limiter(100, "1"), nil, "expected status 429 within 5 requests; got none"},
+		{"no Retry-After", limiter(2, ""), nil, "3 of 3 rate limited responses had no valid Retry-After header"},
+		{"bad Retry-After", limiter(2, "later"), nil, "3 of 3 rate limited responses had no valid Retry-After header"},
+		{"Retry-After optional", limiter(2, ""), map[string]any{"require_retry_after": false}, ""},
+		{"expected unthrottled", limiter(100, ""), map[string]any{"expect_rate_limited": false}, ""},
+		{"unexpectedly throttled", limiter(4, "1"), map[string]any{"expect_rate_limited": false}, "expected no rate limiting; got 1 of 5 requests with status 429"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			conf := map[string]any{"mode": "rateLimit", "samples": 5, "expected_output": "200"}
+			for key, value := range tt.conf {
+				conf[key] = value
+			}
+
+			err := runAgainst(t, server, conf)
+			checkError(t, err, tt.want)
+		})
+	}
+}
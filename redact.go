@@ -0,0 +1,194 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders carry credentials in requests the plugin sends; redact_headers
+// adds to them.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+}
+
+// sensitiveKeys are substrings of body field names whose values are redacted.
+var sensitiveKeys = []string{"pass", "secret", "token", "key"}
+
+func isSensitiveHeader(conf Schema, name string) bool {
+	return containsFold(sensitiveHeaders, name) || containsFold(splitList(conf.RedactHeaders), name)
+}
+
+func isSensitiveKey(name string) bool {
+	name = strings.ToLower(name)
+	for _, key := range sensitiveKeys {
+		if strings.Contains(name, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// secrets returns the credential values conf carries, longest first, so a
+// secret that contains another is redacted whole.
+func secrets(conf Schema) []string {
+	values := []string{
+		conf.AuthPassword,
+		conf.AuthToken,
+		conf.JWTKey,
+		conf.SignSecret,
+	}
+
+	if conf.AuthUsername != "" || conf.AuthPassword != "" {
+		values = append(values, base64.StdEncoding.EncodeToString([]byte(conf.AuthUsername+":"+conf.AuthPassword)))
+	}
+
+	if conf.JWTAlgorithm == "HS256" {
+		values = append(values, conf.JWTVerifyKey)
+	}
+
+	for _, raw := range append(targets(conf), conf.FallbackURL, conf.TunnelURL, conf.ProxyURL) {
+		u, err := url.Parse(raw)
+		if err != nil || u.User == nil {
+			continue
+		}
+		if password, ok := u.User.Password(); ok {
+			values = append(values, password)
+		}
+	}
+
+	// Header fields are parsed loosely, since a malformed field is exactly
+	// what ends up quoted in an error.
+	fields := strings.FieldsFunc(conf.Headers+"\n"+conf.RequestHeaders, func(r rune) bool {
+		return r == ';' || r == '\n'
+	})
+	for _, field := range fields {
+		name, value, ok := strings.Cut(field, ":")
+		if !ok || !isSensitiveHeader(conf, strings.TrimSpace(name)) {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		values = append(values, value)
+		if _, credentials, ok := strings.Cut(value, " "); ok {
+			values = append(values, strings.TrimSpace(credentials))
+		}
+	}
+
+	if conf.Cookies != "" {
+		cookies, err := http.ParseCookie(conf.Cookies)
+		if err == nil {
+			for _, cookie := range cookies {
+				values = append(values, cookie.Value)
+			}
+		}
+	}
+
+	values = append(values, bodySecrets(conf.Body)...)
+
+	unique := []string{}
+	for _, value := range values {
+		if value != "" && !slices.Contains(unique, value) {
+			unique = append(unique, value)
+		}
+	}
+	sort.SliceStable(unique, func(i, j int) bool {
+		return len(unique[i]) > len(unique[j])
+	})
+
+	return unique
+}
+
+// bodySecrets returns the values of password-, secret-, token- and key-like
+// fields of a form or top-level JSON object body.
+func bodySecrets(body string) []string {
+	values := []string{}
+
+	doc := map[string]any{}
+	if json.Unmarshal([]byte(body), &doc) == nil {
+		for name, value := range doc {
+			if text, ok := value.(string); ok && isSensitiveKey(name) {
+				values = append(values, text)
+			}
+		}
+		return values
+	}
+
+	form, err := url.ParseQuery(body)
+	if err != nil {
+		return values
+	}
+	for name, items := range form {
+		if isSensitiveKey(name) {
+			values = append(values, items...)
+		}
+	}
+
+	return values
+}
+
+// redact replaces every secret conf carries in s.
+func redact(conf Schema, s string) string {
+	for _, secret := range secrets(conf) {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+
+	return s
+}
+
+// redactedError hides secrets in an error's message while keeping the
+// original available to errors.Is and errors.As.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactError returns err with every secret conf carries removed from its
+// message, so credentials never reach the scoreboard or a report.
+func redactError(conf Schema, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := redact(conf, err.Error())
+	if msg == err.Error() {
+		return err
+	}
+
+	return &redactedError{msg: msg, err: err}
+}
+
+// redactHeader returns a copy of header with credential-bearing values
+// replaced.
+func redactHeader(conf Schema, header http.Header) http.Header {
+	header = header.Clone()
+	for name, values := range header {
+		for i, value := range values {
+			if isSensitiveHeader(conf, name) {
+				values[i] = redacted
+			} else {
+				values[i] = redact(conf, value)
+			}
+		}
+	}
+
+	return header
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+func assertFinalURL(ctx context.Context, ex *Exchange) error {
+	if ex.Config.FinalURLPattern == "" {
+		return nil
+	}
+
+	pattern, err := regexp.Compile(ex.Config.FinalURLPattern)
+	if err != nil {
+		return fmt.Errorf("invalid final_url_pattern provided: %v; %q", ex.Config.FinalURLPattern, err)
+	}
+
+	final := ex.Response.Request.URL.String()
+	if !pattern.MatchString(final) {
+		return fmt.Errorf("final url %s does not match %q", final, ex.Config.FinalURLPattern)
+	}
+
+	return nil
+}
+
+// redirectChain returns the URLs requested to produce resp, first to last.
+func redirectChain(resp *http.Response) []*url.URL {
+	chain := []*url.URL{}
+	for req := resp.Request; req != nil; {
+		chain = append([]*url.URL{req.URL}, chain...)
+		if req.Response == nil {
+			break
+		}
+		req = req.Response.Request
+	}
+
+	return chain
+}
+
+func assertNoDowngrade(ctx context.Context, ex *Exchange) error {
+	if !ex.Config.ForbidDowngrade {
+		return nil
+	}
+
+	chain := redirectChain(ex.Response)
+	for i := 1; i < len(chain); i++ {
+		if chain[i-1].Scheme == "https" && chain[i].Scheme == "http" {
+			return fmt.Errorf("redirect downgraded from %s to %s", chain[i-1], chain[i])
+		}
+	}
+
+	return nil
+}
+
+// redirectLoopError reports a redirect chain that came back to a request it
+// had already made.
+type redirectLoopError struct {
+	chain []string
+}
+
+func (e *redirectLoopError) Error() string {
+	return fmt.Sprintf("redirect loop: %s", strings.Join(e.chain, " -> "))
+}
+
+// maxVisits is how many times a redirect chain may request the same URL
+// before it is a loop even though cookies changed in between.
+const maxVisits = 3
+
+// checkRedirect follows at most max redirects, after which the last redirect
+// response is returned, and stops with a redirectLoopError as soon as a
+// request repeats an earlier one. A request to the same URL after the chain
+// set new cookies is not a repeat, as when a login page sets a session and
+// redirects to itself, unless the URL has been visited maxVisits times.
+func checkRedirect(max int) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		state := cookieState(req)
+		first, visits := -1, 1
+
+		for i, prev := range via {
+			if prev.Method != req.Method || prev.URL.String() != req.URL.String() {
+				continue
+			}
+			if first < 0 {
+				first = i
+			}
+			visits++
+
+			if cookieState(prev) == state || visits >= maxVisits {
+				chain := []string{}
+				for _, hop := range via[first:] {
+					chain = append(chain, hop.URL.String())
+				}
+				return &redirectLoopError{chain: append(chain, req.URL.String())}
+			}
+		}
+
+		// Past the limit the redirect itself becomes the response.
+		if len(via) > max {
+			return http.ErrUseLastResponse
+		}
+
+		return nil
+	}
+}
+
+// cookieState returns the cookies set by the redirect responses that led to
+// req, as sorted name=value pairs.
+func cookieState(req *http.Request) string {
+	responses := []*http.Response{}
+	for r := req; r != nil && r.Response != nil; r = r.Response.Request {
+		responses = append(responses, r.Response)
+	}
+
+	// Oldest first, so a cookie set again later takes its newer value.
+	cookies := map[string]string{}
+	for i := len(responses) - 1; i >= 0; i-- {
+		for _, cookie := range responses[i].Cookies() {
+			cookies[cookie.Name] = cookie.Value
+		}
+	}
+
+	pairs := []string{}
+	for _, name := range slices.Sorted(maps.Keys(cookies)) {
+		pairs = append(pairs, name+"="+cookies[name])
+	}
+
+	return strings.Join(pairs, "; ")
+}
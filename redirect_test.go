@@ -0,0 +1,82 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRedirectLoops(t *testing.T) {
+	var counter atomic.Int64
+
+	mux := http.NewServeMux()
+	// /login sets a session and redirects to itself until the session comes
+	// back.
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session"); err == nil {
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+		http.Redirect(w, r, "/login", http.StatusFound)
+	})
+	mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/b", http.StatusFound)
+	})
+	mux.HandleFunc("/b", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/a", http.StatusFound)
+	})
+	// /churn sets a new cookie value on every visit and never settles.
+	mux.HandleFunc("/churn", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "n", Value: strconv.FormatInt(counter.Add(1), 10)})
+		http.Redirect(w, r, "/churn", http.StatusFound)
+	})
+	mux.HandleFunc("/hop/", func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/hop/"))
+		if n > 0 {
+			http.Redirect(w, r, "/hop/"+strconv.Itoa(n-1), http.StatusFound)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		path string
+		conf map[string]any
+		want string
+	}{
+		{"same url after setting a cookie", "/login", map[string]any{"cookie_jar": true}, ""},
+		{"same url with the same cookie", "/login", nil, "redirect loop: " + server.URL + "/login -> " + server.URL + "/login"},
+		{"two url loop", "/a", nil, "redirect loop: " + server.URL + "/a -> " + server.URL + "/b -> " + server.URL + "/a"},
+		{"cookies keep changing", "/churn", map[string]any{"cookie_jar": true}, "redirect loop"},
+		{"chain within limit", "/hop/3", nil, ""},
+		{"chain over limit", "/hop/3", map[string]any{"max_redirects": 2}, "got: 302"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := map[string]any{"url": server.URL + tt.path, "expected_output": "200"}
+			for key, value := range tt.conf {
+				doc[key] = value
+			}
+			config, _ := json.Marshal(doc)
+
+			err := New().Run(context.Background(), string(config))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Run() = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Run() = %v; want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
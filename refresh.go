@@ -0,0 +1,86 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// followMetaRefresh replaces ex.Response with the page an HTML
+// <meta http-equiv="refresh"> points at, up to max_meta_refresh times, so
+// matchers see the page a browser would end up on.
+func followMetaRefresh(ctx context.Context, ex *Exchange) error {
+	for hop := 0; hop < ex.Config.MaxMetaRefresh; hop++ {
+		media := mediaType(ex.Response.Header.Get("Content-Type"))
+		if media != "text/html" && media != "application/xhtml+xml" {
+			return nil
+		}
+
+		body, err := readBody(ex)
+		if err != nil {
+			return err
+		}
+
+		target, ok := metaRefreshTarget(ex.Response.Request.URL, string(body))
+		if !ok {
+			return nil
+		}
+
+		resp, err := ex.fetch(ctx, target)
+		if err != nil {
+			return fmt.Errorf("encounted error while following meta refresh to %s: %v", target, err)
+		}
+
+		drainBody(ex.Response.Body)
+		ex.Response = resp
+		ex.body, ex.bodyErr, ex.read = nil, nil, false
+		ex.Result.Note("followed meta refresh to %s", target)
+	}
+
+	return nil
+}
+
+// metaRefreshTarget returns the absolute URL of the first meta refresh in
+// doc, whose content attribute looks like `5; url=/next`. A refresh without
+// a URL reloads the same page and is ignored.
+func metaRefreshTarget(base *url.URL, doc string) (string, bool) {
+	for _, element := range scanHTML(doc) {
+		if element.Tag != "meta" {
+			continue
+		}
+
+		equiv, _ := element.Attr("http-equiv")
+		if !strings.EqualFold(strings.TrimSpace(equiv), "refresh") {
+			continue
+		}
+
+		content, _ := element.Attr("content")
+		_, raw, ok := strings.Cut(content, ";")
+		if !ok {
+			_, raw, ok = strings.Cut(content, ",")
+		}
+		if !ok {
+			return "", false
+		}
+
+		raw = strings.TrimSpace(raw)
+		if len(raw) >= 3 && strings.EqualFold(raw[:3], "url") {
+			raw = strings.TrimSpace(raw[3:])
+			raw = strings.TrimSpace(strings.TrimPrefix(raw, "="))
+		}
+		raw = strings.Trim(raw, `"'`)
+		if raw == "" {
+			return "", false
+		}
+
+		ref, err := url.Parse(raw)
+		if err != nil {
+			return "", false
+		}
+
+		return base.ResolveReference(ref).String(), true
+	}
+
+	return "", false
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Report is the machine-readable outcome of one check, for dashboards that
+// should not have to parse error strings.
+type Report struct {
+	Time      time.Time `json:"time"`
+	URL       string    `json:"url"`
+	MatchType string    `json:"match_type"`
+	Mode      string    `json:"mode"`
+	Passed    bool      `json:"passed"`
+	Error     string    `json:"error,omitempty"`
+	Result    *Result   `json:"result,omitempty"`
+}
+
+// WithReportFunc makes the checker call fn with the report of every check it
+// runs, after the check finishes and before Run or Check returns.
+func WithReportFunc(fn func(Report)) Option {
+	return func(c *Checker) {
+		c.report = fn
+	}
+}
+
+// WithReportWriter makes the checker write the report of every check it runs
+// to w as one line of JSON. Writes are serialized; write errors are ignored
+// so telemetry can never fail a check.
+func WithReportWriter(w io.Writer) Option {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(w)
+
+	return WithReportFunc(func(report Report) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		_ = encoder.Encode(report)
+	})
+}
+
+func (c *Checker) emitReport(conf Schema, res *Result, err error) {
+	if c.report == nil {
+		return
+	}
+
+	report := Report{
+		Time:      time.Now(),
+		URL:       redact(conf, conf.URL),
+		MatchType: conf.MatchType,
+		Mode:      conf.Mode,
+		Passed:    err == nil,
+		Result:    res,
+	}
+	if err != nil {
+		report.Error = err.Error()
+	}
+
+	c.report(report)
+}
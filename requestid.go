@@ -0,0 +1,56 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// setRequestID gives req a fresh random ID in request_id_header.
+func setRequestID(conf Schema, req *http.Request) error {
+	if conf.RequestIDHeader == "" {
+		return nil
+	}
+
+	id, err := nonce()
+	if err != nil {
+		return err
+	}
+	req.Header.Set(conf.RequestIDHeader, id)
+
+	return nil
+}
+
+// assertRequestID expects the request ID sent in request_id_header to come
+// back in any response header or in the body, which a cache or a static
+// decoy page cannot do.
+func assertRequestID(ctx context.Context, ex *Exchange) error {
+	if ex.Config.RequestIDHeader == "" {
+		return nil
+	}
+
+	id := ex.Request.Header.Get(ex.Config.RequestIDHeader)
+	if id == "" {
+		return nil
+	}
+
+	for _, values := range ex.Response.Header {
+		for _, value := range values {
+			if strings.Contains(value, id) {
+				return nil
+			}
+		}
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(string(body), id) {
+		return fmt.Errorf("request id %s sent in %s was not echoed in the response", id, ex.Config.RequestIDHeader)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Result is what a check observed beyond pass or fail. Notes never affect the
+// outcome; they give operators context such as detected content changes.
+type Result struct {
+	mu      sync.Mutex
+	Notes   []string `json:"notes,omitempty"`
+	ALPN    string   `json:"alpn,omitempty"`
+	Timings Timings  `json:"timings"`
+
+	// LatencyMs is Timings.Total in whole milliseconds, recorded for every
+	// request so responsiveness can be graphed whether or not it is asserted.
+	LatencyMs int64 `json:"latency_ms"`
+
+	// Status is the status code of the last response received.
+	Status int `json:"status,omitempty"`
+	// Score is the fraction of credit status_scores awards Status, or nil
+	// when status_scores is unset or no response was received.
+	Score *float64 `json:"score,omitempty"`
+
+	// Aggregate summarizes every attempt of a load or sample mode check.
+	Aggregate *Aggregate `json:"aggregate,omitempty"`
+}
+
+// clearAttempt forgets what the last attempt recorded: its status, score,
+// timings and ALPN, and any notes after the first kept. The caller must hold
+// r's lock.
+func (r *Result) clearAttempt(kept int) {
+	r.Notes = r.Notes[:kept]
+	r.ALPN = ""
+	r.Timings = Timings{}
+	r.LatencyMs = 0
+	r.Status = 0
+	r.Score = nil
+}
+
+// update applies fn to r while holding its lock.
+func (r *Result) update(fn func(r *Result)) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fn(r)
+}
+
+// Note records an observation, ignoring exact repeats such as the same
+// finding from every request of a burst. It is safe for concurrent use.
+func (r *Result) Note(format string, args ...any) {
+	if r == nil {
+		return
+	}
+
+	note := fmt.Sprintf(format, args...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if slices.Contains(r.Notes, note) {
+		return
+	}
+	r.Notes = append(r.Notes, note)
+}
+
+// Check runs config with the package-level checker and returns its result.
+func Check(ctx context.Context, config string) (*Result, error) {
+	return defaultChecker.Check(ctx, config)
+}
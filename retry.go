@@ -0,0 +1,106 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+)
+
+// runRetries runs a single attempt, repeating it up to retries more times
+// while it fails for a reason worth retrying: no response at all, such as a
+// timeout or reset connection, or a status listed in retry_on_status. Any
+// other failure is definitive and returned at once.
+func (c *Checker) runRetries(ctx context.Context, conf Schema, res *Result) error {
+	retryOn, err := parseStatusList(conf.RetryOnStatus)
+	if err != nil {
+		return err
+	}
+
+	kept := 0
+	res.update(func(r *Result) {
+		kept = len(r.Notes)
+	})
+
+	for try := 0; ; try++ {
+		err = c.attempt(ctx, conf, res)
+		if err == nil || try >= conf.Retries || !retryable(ctx, err, res, retryOn) {
+			return err
+		}
+
+		// The next attempt reports only what it observes itself.
+		res.update(func(r *Result) {
+			r.clearAttempt(kept)
+		})
+		res.Note("attempt %d of %d failed, retrying: %v", try+1, conf.Retries+1, err)
+		res.update(func(r *Result) {
+			kept = len(r.Notes)
+		})
+
+		err = sleep(ctx, time.Duration(conf.RetryDelayMs)*time.Millisecond)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func retryable(ctx context.Context, err error, res *Result, retryOn []int) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return !verificationFailed(err)
+	}
+
+	status := 0
+	res.update(func(r *Result) {
+		status = r.Status
+	})
+
+	return slices.Contains(retryOn, status)
+}
+
+// verificationFailed reports whether err is a TLS handshake that failed on
+// the certificate or an alert rather than on the network. It fails the same
+// way however often it is repeated.
+func verificationFailed(err error) bool {
+	var remote *net.OpError
+	if errors.As(err, &remote) && remote.Op == "remote error" {
+		return true
+	}
+
+	var alert tls.AlertError
+	var verification *tls.CertificateVerificationError
+	var unknown x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+
+	return errors.As(err, &alert) ||
+		errors.As(err, &verification) ||
+		errors.As(err, &unknown) ||
+		errors.As(err, &invalid) ||
+		errors.As(err, &hostname)
+}
+
+// sleep waits for d or until ctx is done, whichever is first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("encounted error while waiting: %v", ctx.Err())
+	}
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		retries  int
+		want     string
+		requests int32
+	}{
+		{"retried until success", []int{503, 502, 200}, 2, "", 3},
+		{"retries exhausted", []int{503, 503, 503}, 1, "got: 503", 2},
+		{"unlisted status is final", []int{500, 200}, 2, "got: 500", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := requests.Add(1)
+				w.WriteHeader(tt.statuses[n-1])
+			}))
+			defer server.Close()
+
+			err := runAgainst(t, server, map[string]any{
+				"expected_output": "200",
+				"retries":         tt.retries,
+				"retry_delay_ms":  0,
+			})
+			checkError(t, err, tt.want)
+
+			if got := requests.Load(); got != tt.requests {
+				t.Errorf("server saw %d requests; want %d", got, tt.requests)
+			}
+		})
+	}
+}
+
+func TestRetryClearsAttempt(t *testing.T) {
+	// The first attempt gets a scored 503; the second gets no response.
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer server.Close()
+
+	config, _ := json.Marshal(map[string]any{
+		"url":             server.URL,
+		"expected_output": "200",
+		"status_scores":   "503=0.25",
+		"retries":         1,
+		"retry_delay_ms":  0,
+	})
+
+	res, err := New(WithClient(server.Client())).Check(context.Background(), string(config))
+	if err == nil {
+		t.Fatalf("Check() = nil; want an error")
+	}
+	if res.Status != 0 {
+		t.Errorf("Status = %d; want 0", res.Status)
+	}
+	if res.Score != nil {
+		t.Errorf("Score = %v; want nil", *res.Score)
+	}
+	if len(res.Notes) != 1 || !strings.Contains(res.Notes[0], "attempt 1 of 2 failed") {
+		t.Errorf("Notes = %q; want only the retry note", res.Notes)
+	}
+}
+
+func TestRetrySkipsRejectedCertificate(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	config, _ := json.Marshal(map[string]any{
+		"url":            server.URL,
+		"retries":        2,
+		"retry_delay_ms": 0,
+	})
+
+	// The default client does not trust the test server's certificate.
+	err := New().Run(context.Background(), string(config))
+	checkError(t, err, "certificate")
+
+	if got := conns.Load(); got != 1 {
+		t.Errorf("server saw %d connections; want 1", got)
+	}
+}
+
+func TestVerificationFailed(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"unknown authority", &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}, true},
+		{"hostname", x509.HostnameError{Certificate: &x509.Certificate{}, Host: "example.com"}, true},
+		{"alert", &net.OpError{Op: "remote error", Err: tls.AlertError(40)}, true},
+		{"refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, false},
+		{"timeout", context.DeadlineExceeded, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := &requestError{err: fmt.Errorf("Get: %w", tt.err)}
+			if got := verificationFailed(err); got != tt.want {
+				t.Errorf("verificationFailed(%v) = %v; want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Aggregate summarizes the attempts of a multi-request check.
+type Aggregate struct {
+	Samples       int     `json:"samples"`
+	Successes     int     `json:"successes"`
+	SuccessRate   float64 `json:"success_rate"`
+	MeanLatencyMs int64   `json:"mean_latency_ms"`
+	MaxLatencyMs  int64   `json:"max_latency_ms"`
+}
+
+func aggregate(samples []sample) Aggregate {
+	agg := Aggregate{Samples: len(samples)}
+
+	var total, slowest time.Duration
+	for _, s := range samples {
+		if s.err == nil {
+			agg.Successes++
+		}
+		total += s.latency
+		slowest = max(slowest, s.latency)
+	}
+
+	if len(samples) > 0 {
+		agg.SuccessRate = float64(agg.Successes) * 100 / float64(len(samples))
+		agg.MeanLatencyMs = (total / time.Duration(len(samples))).Milliseconds()
+	}
+	agg.MaxLatencyMs = slowest.Milliseconds()
+
+	return agg
+}
+
+func recordAggregate(res *Result, samples []sample) Aggregate {
+	agg := aggregate(samples)
+	res.update(func(r *Result) {
+		r.Aggregate = &agg
+	})
+
+	return agg
+}
+
+func validateSample(conf Schema) error {
+	if conf.Samples < 1 {
+		return fmt.Errorf("samples must be at least 1; got: %d", conf.Samples)
+	}
+
+	if conf.AttemptDelayMs < 0 {
+		return fmt.Errorf("attempt_delay_ms must not be negative; got: %d", conf.AttemptDelayMs)
+	}
+
+	return nil
+}
+
+// runSample makes samples attempts one after another, attempt_delay_ms
+// apart, records their aggregate in the result, and passes when at least
+// min_success_rate percent succeed.
+func (c *Checker) runSample(ctx context.Context, conf Schema, res *Result) error {
+	samples := []sample{}
+	var lastErr error
+
+	for i := 0; i < conf.Samples; i++ {
+		if i > 0 {
+			err := sleep(ctx, time.Duration(conf.AttemptDelayMs)*time.Millisecond)
+			if err != nil {
+				lastErr = err
+				break
+			}
+		}
+
+		start := time.Now()
+		err := c.attempt(ctx, conf, res)
+		samples = append(samples, sample{latency: time.Since(start), err: err})
+		if err != nil {
+			lastErr = err
+		}
+	}
+
+	// Samples cut short by cancellation count as failures.
+	for len(samples) < conf.Samples {
+		samples = append(samples, sample{err: lastErr})
+	}
+
+	agg := recordAggregate(res, samples)
+	if agg.SuccessRate < float64(conf.MinSuccessRate) {
+		return fmt.Errorf("success rate %.0f%% below required %d%%; last error: %v", agg.SuccessRate, conf.MinSuccessRate, lastErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAggregate(t *testing.T) {
+	samples := []sample{
+		{latency: 10 * time.Millisecond},
+		{latency: 30 * time.Millisecond},
+		{latency: 50 * time.Millisecond, err: context.DeadlineExceeded},
+		{latency: 30 * time.Millisecond},
+	}
+
+	got := aggregate(samples)
+	want := Aggregate{Samples: 4, Successes: 3, SuccessRate: 75, MeanLatencyMs: 30, MaxLatencyMs: 50}
+	if got != want {
+		t.Errorf("aggregate() = %+v; want %+v", got, want)
+	}
+
+	if got := aggregate(nil); got != (Aggregate{}) {
+		t.Errorf("aggregate(nil) = %+v; want zero", got)
+	}
+}
+
+func TestSample(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		minRate  int
+		want     string
+		agg      Aggregate
+	}{
+		{"all pass", []int{200, 200, 200, 200}, 100, "", Aggregate{Samples: 4, Successes: 4, SuccessRate: 100}},
+		{"one flake allowed", []int{200, 500, 200, 200}, 75, "", Aggregate{Samples: 4, Successes: 3, SuccessRate: 75}},
+		{"one flake too many", []int{200, 500, 200, 200}, 80, "success rate 75% below required 80%; last error: expected status code: 200; got: 500", Aggregate{Samples: 4, Successes: 3, SuccessRate: 75}},
+		{"failure after pass", []int{500, 200, 200, 500}, 50, "", Aggregate{Samples: 4, Successes: 2, SuccessRate: 50}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statuses[requests.Add(1)-1])
+			}))
+			defer server.Close()
+
+			config, _ := json.Marshal(map[string]any{
+				"url":              server.URL,
+				"mode":             "sample",
+				"expected_output":  "200",
+				"samples":          len(tt.statuses),
+				"min_success_rate": tt.minRate,
+				"attempt_delay_ms": 0,
+			})
+
+			res, err := New(WithClient(server.Client())).Check(context.Background(), string(config))
+			checkError(t, err, tt.want)
+
+			if got := requests.Load(); int(got) != len(tt.statuses) {
+				t.Errorf("server saw %d requests; want %d", got, len(tt.statuses))
+			}
+			if res.Aggregate == nil {
+				t.Fatalf("Aggregate = nil; want %+v", tt.agg)
+			}
+			got := *res.Aggregate
+			got.MeanLatencyMs, got.MaxLatencyMs = 0, 0
+			if got != tt.agg {
+				t.Errorf("Aggregate = %+v; want %+v", got, tt.agg)
+			}
+		})
+	}
+}
+
+func TestSampleCancelled(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	config, _ := json.Marshal(map[string]any{
+		"url":              server.URL,
+		"mode":             "sample",
+		"expected_output":  "200",
+		"samples":          4,
+		"min_success_rate": 50,
+		"attempt_delay_ms": 10000,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The first sample passes; the three cut short count as failures.
+	res, err := New().Check(ctx, string(config))
+	checkError(t, err, "success rate 25% below required 50%")
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server saw %d requests; want 1", got)
+	}
+	if res.Aggregate == nil || res.Aggregate.Samples != 4 || res.Aggregate.Successes != 1 {
+		t.Errorf("Aggregate = %+v; want 1 of 4 successes", res.Aggregate)
+	}
+}
+
+func TestValidateSample(t *testing.T) {
+	tests := []struct {
+		name string
+		conf Schema
+		want string
+	}{
+		{"valid", Schema{Samples: 3, AttemptDelayMs: 0}, ""},
+		{"no samples", Schema{Samples: 0}, "samples must be at least 1; got: 0"},
+		{"negative delay", Schema{Samples: 3, AttemptDelayMs: -1}, "attempt_delay_ms must not be negative; got: -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkError(t, validateSample(tt.conf), tt.want)
+		})
+	}
+}
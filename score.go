@@ -0,0 +1,58 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseStatusScores parses status_scores, a list of status=fraction pairs
+// such as "200=1,503=0.25,404=0".
+func parseStatusScores(raw string) (map[int]float64, error) {
+	scores := map[int]float64{}
+	for _, item := range splitList(raw) {
+		status, fraction, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("status_scores format must be \"status=score,status=score\" ; got: %v", raw)
+		}
+
+		code, err := strconv.Atoi(strings.TrimSpace(status))
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("invalid status code provided: %v", status)
+		}
+
+		score, err := strconv.ParseFloat(strings.TrimSpace(fraction), 64)
+		if err != nil || score < 0 || score > 1 {
+			return nil, fmt.Errorf("status score must be between 0 and 1; got: %v", fraction)
+		}
+
+		scores[code] = score
+	}
+
+	return scores, nil
+}
+
+func observeStatus(ex *Exchange) {
+	status := ex.Response.StatusCode
+	ex.Result.update(func(r *Result) {
+		r.Status = status
+	})
+}
+
+// observeScore records the partial credit status_scores awards the response
+// status. Unlisted statuses score 0.
+func observeScore(ex *Exchange) {
+	if ex.Config.StatusScores == "" {
+		return
+	}
+
+	scores, err := parseStatusScores(ex.Config.StatusScores)
+	if err != nil {
+		return
+	}
+
+	score := scores[ex.Response.StatusCode]
+	ex.Result.update(func(r *Result) {
+		r.Score = &score
+	})
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+var signHashes = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+func validateSigning(conf Schema) error {
+	if _, ok := signHashes[conf.SignAlgorithm]; !ok {
+		return fmt.Errorf("invalid sign_algorithm provided: %v", conf.SignAlgorithm)
+	}
+
+	if conf.SignEncoding != "hex" && conf.SignEncoding != "base64" {
+		return fmt.Errorf("invalid sign_encoding provided: %v", conf.SignEncoding)
+	}
+
+	if conf.SignHeader == "" || strings.ContainsAny(conf.SignHeader, ": \t") {
+		return fmt.Errorf("invalid sign_header provided: %q", conf.SignHeader)
+	}
+
+	_, err := parseSignTemplate(conf.SignTemplate, time.Time{}, http.Header{})
+	return err
+}
+
+// parseSignTemplate parses raw with the template helpers and a header
+// function reading the request's headers.
+func parseSignTemplate(raw string, now time.Time, header http.Header) (*template.Template, error) {
+	funcs := templateFuncs(now)
+	funcs["header"] = header.Get
+
+	tmpl, err := template.New("sign_template").Option("missingkey=error").Funcs(funcs).Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sign_template provided: %v; %q", raw, err)
+	}
+
+	return tmpl, nil
+}
+
+// signRequest computes an HMAC over sign_template, filled from the finished
+// request, and sends it in sign_header. Webhook-style APIs differ only in
+// what they sign and how they encode it, e.g. GitHub's:
+//
+//	sign_template: {{.body}}
+//	sign_header:   X-Hub-Signature-256
+//	sign_prefix:   sha256=
+func signRequest(conf Schema, req *http.Request, now time.Time) error {
+	if conf.SignSecret == "" {
+		return nil
+	}
+
+	newHash, ok := signHashes[conf.SignAlgorithm]
+	if !ok {
+		return fmt.Errorf("invalid sign_algorithm provided: %v", conf.SignAlgorithm)
+	}
+
+	tmpl, err := parseSignTemplate(conf.SignTemplate, now, req.Header)
+	if err != nil {
+		return err
+	}
+
+	body := ""
+	if conf.ContentType != "empty" {
+		body = conf.Body
+	}
+
+	var message strings.Builder
+	err = tmpl.Execute(&message, map[string]string{
+		"method": req.Method,
+		"path":   req.URL.EscapedPath(),
+		"query":  req.URL.RawQuery,
+		"host":   req.Host,
+		"body":   body,
+	})
+	if err != nil {
+		return fmt.Errorf("encounted error while filling sign_template: %v", err)
+	}
+
+	mac := hmac.New(newHash, []byte(conf.SignSecret))
+	mac.Write([]byte(message.String()))
+	sum := mac.Sum(nil)
+
+	signature := hex.EncodeToString(sum)
+	if conf.SignEncoding == "base64" {
+		signature = base64.StdEncoding.EncodeToString(sum)
+	}
+
+	req.Header.Set(conf.SignHeader, conf.SignPrefix+signature)
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func hmacOf(newHash func() hash.Hash, secret, message string) []byte {
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+func TestSignRequest(t *testing.T) {
+	base := Schema{
+		SignSecret:    "s3cret",
+		SignAlgorithm: "sha256",
+		SignTemplate:  "{{.body}}",
+		SignHeader:    "X-Signature",
+		SignEncoding:  "hex",
+		ContentType:   "json",
+		Body:          `{"a":1}`,
+	}
+
+	tests := []struct {
+		name   string
+		change func(*Schema)
+		header string
+		want   string
+	}{
+		{"body", func(c *Schema) {}, "X-Signature", hex.EncodeToString(hmacOf(sha256.New, "s3cret", `{"a":1}`))},
+		{"github style", func(c *Schema) {
+			c.SignHeader, c.SignPrefix = "X-Hub-Signature-256", "sha256="
+		}, "X-Hub-Signature-256", "sha256=" + hex.EncodeToString(hmacOf(sha256.New, "s3cret", `{"a":1}`))},
+		{"request parts", func(c *Schema) {
+			c.SignTemplate = `{{.method}} {{.path}}?{{.query}} {{.host}} {{header "X-Team"}}`
+		}, "X-Signature", hex.EncodeToString(hmacOf(sha256.New, "s3cret", "POST /hook%20a?x=1 example.com blue"))},
+		{"base64 sha512", func(c *Schema) {
+			c.SignAlgorithm, c.SignEncoding = "sha512", "base64"
+		}, "X-Signature", base64.StdEncoding.EncodeToString(hmacOf(sha512.New, "s3cret", `{"a":1}`))},
+		{"sha1", func(c *Schema) { c.SignAlgorithm = "sha1" }, "X-Signature", hex.EncodeToString(hmacOf(sha1.New, "s3cret", `{"a":1}`))},
+		{"empty content type", func(c *Schema) { c.ContentType = "empty" }, "X-Signature", hex.EncodeToString(hmacOf(sha256.New, "s3cret", ""))},
+		{"no secret", func(c *Schema) { c.SignSecret = "" }, "X-Signature", ""},
+		{"timestamp", func(c *Schema) {
+			c.SignTemplate = "{{timestamp}}.{{.body}}"
+		}, "X-Signature", hex.EncodeToString(hmacOf(sha256.New, "s3cret", `1700000000.{"a":1}`))},
+		{"missing header", func(c *Schema) {
+			c.SignTemplate = `{{header "X-Missing"}}|{{.body}}`
+		}, "X-Signature", hex.EncodeToString(hmacOf(sha256.New, "s3cret", `|{"a":1}`))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := base
+			tt.change(&conf)
+
+			req, err := http.NewRequest("POST", "http://example.com/hook%20a?x=1", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("X-Team", "blue")
+
+			err = signRequest(conf, req, time.Unix(1700000000, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := req.Header.Get(tt.header); got != tt.want {
+				t.Errorf("%s = %q; want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSigning(t *testing.T) {
+	base := Schema{SignAlgorithm: "sha256", SignTemplate: "{{.body}}", SignHeader: "X-Signature", SignEncoding: "hex"}
+
+	tests := []struct {
+		name   string
+		change func(*Schema)
+		err    string
+	}{
+		{"valid", func(c *Schema) {}, ""},
+		{"algorithm", func(c *Schema) { c.SignAlgorithm = "md5" }, "invalid sign_algorithm provided: md5"},
+		{"encoding", func(c *Schema) { c.SignEncoding = "base32" }, "invalid sign_encoding provided: base32"},
+		{"header with colon", func(c *Schema) { c.SignHeader = "X-Sig:" }, "invalid sign_header provided"},
+		{"empty header", func(c *Schema) { c.SignHeader = "" }, "invalid sign_header provided"},
+		{"template", func(c *Schema) { c.SignTemplate = "{{.body" }, "invalid sign_template provided"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := base
+			tt.change(&conf)
+			checkError(t, validateSigning(conf), tt.err)
+		})
+	}
+}
+
+func TestSignRequestUnknownField(t *testing.T) {
+	conf := Schema{SignSecret: "s3cret", SignAlgorithm: "sha256", SignTemplate: "{{.user}}", SignHeader: "X-Signature", SignEncoding: "hex"}
+
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	err := signRequest(conf, req, time.Now())
+	checkError(t, err, "encounted error while filling sign_template")
+	if got := req.Header.Get("X-Signature"); got != "" {
+		t.Errorf("X-Signature = %q; want it unset", got)
+	}
+}
+
+func TestSignedRequestVerifies(t *testing.T) {
+	// The server recomputes the GitHub-style signature over what it received.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		want := "sha256=" + hex.EncodeToString(hmacOf(sha256.New, "s3cret", string(body)))
+		if !hmac.Equal([]byte(r.Header.Get("X-Hub-Signature-256")), []byte(want)) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		secret string
+		want   string
+	}{
+		{"right secret", "s3cret", ""},
+		{"wrong secret", "guess", "expected status code: 200; got: 401"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runAgainst(t, server, map[string]any{
+				"verb":            "POST",
+				"content_type":    "application/json",
+				"body":            `{"action":"opened"}`,
+				"expected_output": "200",
+				"sign_secret":     tt.secret,
+				"sign_header":     "X-Hub-Signature-256",
+				"sign_prefix":     "sha256=",
+			})
+			checkError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+)
+
+// sitemapDocument covers both a urlset and a sitemapindex; only one of the
+// lists is populated for a given document.
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapEntry `xml:"url"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc string `xml:"loc"`
+}
+
+// sitemapMatcher expects the body to be a sitemap listing at least one URL
+// and, when sitemap_samples is set, that many random entries to return 200.
+type sitemapMatcher struct{}
+
+func (sitemapMatcher) ValidateConfig(conf Schema) error {
+	if conf.SitemapSamples < 0 {
+		return fmt.Errorf("sitemap_samples must not be negative; got: %d", conf.SitemapSamples)
+	}
+
+	return nil
+}
+
+func (sitemapMatcher) Match(ctx context.Context, ex *Exchange) error {
+	if ex.Response.StatusCode != http.StatusOK {
+		return fmt.Errorf("expected status code: %d; got: %d", http.StatusOK, ex.Response.StatusCode)
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	doc := sitemapDocument{}
+
+	err = xml.Unmarshal(body, &doc)
+	if err != nil {
+		return fmt.Errorf("response body is not a valid sitemap: %v", err)
+	}
+
+	if doc.XMLName.Local != "urlset" && doc.XMLName.Local != "sitemapindex" {
+		return fmt.Errorf("response body is not a sitemap; root element is %q", doc.XMLName.Local)
+	}
+
+	locs := []string{}
+	for _, entry := range append(doc.URLs, doc.Sitemaps...) {
+		if loc := strings.TrimSpace(entry.Loc); loc != "" {
+			locs = append(locs, loc)
+		}
+	}
+
+	if len(locs) == 0 {
+		return fmt.Errorf("sitemap lists no URLs")
+	}
+
+	rand.Shuffle(len(locs), func(i, j int) { locs[i], locs[j] = locs[j], locs[i] })
+	if len(locs) > ex.Config.SitemapSamples {
+		locs = locs[:ex.Config.SitemapSamples]
+	}
+
+	for _, loc := range locs {
+		resp, err := ex.fetch(ctx, loc)
+		if err != nil {
+			return fmt.Errorf("sitemap URL %s: %v", loc, err)
+		}
+		drainBody(resp.Body)
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("sitemap URL %s: expected status code: %d; got: %d", loc, http.StatusOK, resp.StatusCode)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSitemap(t *testing.T) {
+	tests := []struct {
+		name    string
+		sitemap string
+		samples int
+		fetches int32
+		want    string
+	}{
+		{"urlset", `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>{base}/a</loc></url><url><loc>{base}/b</loc></url></urlset>`, 2, 2, ""},
+		{"index", `<sitemapindex><sitemap><loc>{base}/a</loc></sitemap></sitemapindex>`, 1, 1, ""},
+		{"samples capped at entries", `<urlset><url><loc>{base}/a</loc></url></urlset>`, 5, 1, ""},
+		{"no samples", `<urlset><url><loc>{base}/missing</loc></url></urlset>`, 0, 0, ""},
+		{"broken entry", `<urlset><url><loc>{base}/missing</loc></url></urlset>`, 1, 1, "/missing: expected status code: 200; got: 404"},
+		{"blank locs", `<urlset><url><loc> </loc></url></urlset>`, 1, 0, "sitemap lists no URLs"},
+		{"wrong root", `<rss><channel></channel></rss>`, 1, 0, `root element is "rss"`},
+		{"malformed", `<urlset><url>`, 1, 0, "response body is not a valid sitemap"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetches atomic.Int32
+			var base string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/sitemap.xml":
+					w.Write([]byte(strings.ReplaceAll(tt.sitemap, "{base}", base)))
+				case "/missing":
+					fetches.Add(1)
+					w.WriteHeader(http.StatusNotFound)
+				default:
+					fetches.Add(1)
+				}
+			}))
+			defer server.Close()
+			base = server.URL
+
+			err := runAgainst(t, server, map[string]any{
+				"url":             server.URL + "/sitemap.xml",
+				"match_type":      "sitemap",
+				"sitemap_samples": tt.samples,
+			})
+			checkError(t, err, tt.want)
+
+			if got := fetches.Load(); got != tt.fetches {
+				t.Errorf("%d sitemap URLs fetched; want %d", got, tt.fetches)
+			}
+		})
+	}
+}
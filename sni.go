@@ -0,0 +1,111 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// sniMismatchMatcher resends the configured request with sni_name as the TLS
+// server name while the Host header keeps naming the url's host, and checks
+// the server's policy: reject expects a failed handshake or a 4xx status
+// such as 421 Misdirected Request; default expects it to be served, by the
+// default vhost, containing expected_output when set.
+type sniMismatchMatcher struct{}
+
+func (sniMismatchMatcher) ValidateConfig(conf Schema) error {
+	if conf.SNIName == "" {
+		return fmt.Errorf("sniMismatch requires sni_name to be provided")
+	}
+
+	if conf.MismatchPolicy != "reject" && conf.MismatchPolicy != "default" {
+		return fmt.Errorf("invalid mismatch_policy provided: %v", conf.MismatchPolicy)
+	}
+
+	return nil
+}
+
+func (sniMismatchMatcher) Match(ctx context.Context, ex *Exchange) error {
+	conf := ex.Config
+
+	req, err := ex.NewRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	if req.URL.Scheme != "https" {
+		return fmt.Errorf("sniMismatch requires an https url; got: %v", req.URL)
+	}
+
+	if strings.EqualFold(req.URL.Hostname(), conf.SNIName) {
+		return fmt.Errorf("sni_name must differ from the url host %s", req.URL.Hostname())
+	}
+
+	// Only the server's routing is scored, not whether its certificate
+	// covers a name chosen to be wrong, so a handshake error means rejection.
+	client, err := withServerName(ex.Client, conf.SNIName)
+	if err != nil {
+		return err
+	}
+	client.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify = true
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if conf.MismatchPolicy == "reject" {
+			if handshakeRejected(err) {
+				return nil
+			}
+			return fmt.Errorf("expected the mismatched request to be rejected by the TLS handshake; got: %v", err)
+		}
+		return fmt.Errorf("expected the mismatched request to be served; got: %v", err)
+	}
+	defer drainBody(resp.Body)
+
+	if conf.MismatchPolicy == "reject" {
+		if resp.StatusCode >= 400 && resp.StatusCode <= 499 {
+			return nil
+		}
+		return fmt.Errorf("expected the mismatched request to be rejected; got status %d", resp.StatusCode)
+	}
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("expected the mismatched request to be served; got status %d", resp.StatusCode)
+	}
+
+	if conf.ExpectedOutput == "" {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("encountered error while reading response body: %v", err)
+	}
+
+	if !strings.Contains(string(body), conf.ExpectedOutput) {
+		return fmt.Errorf("expected output not found in default vhost response body")
+	}
+
+	return nil
+}
+
+// handshakeRejected reports whether err is the server refusing the TLS
+// handshake, with an alert or by not speaking TLS, as opposed to a refused
+// connection or timeout that says nothing about its SNI policy. The
+// certificate is never verified here, so it cannot be the cause. Alerts the
+// server sends arrive as a net.OpError with Op "remote error".
+func handshakeRejected(err error) bool {
+	var remote *net.OpError
+	if errors.As(err, &remote) && remote.Op == "remote error" {
+		return true
+	}
+
+	var alert tls.AlertError
+	var record tls.RecordHeaderError
+
+	return errors.As(err, &alert) || errors.As(err, &record)
+}
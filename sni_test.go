@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestHandshakeRejected(t *testing.T) {
+	wrap := func(err error) error {
+		return &url.Error{Op: "Get", URL: "https://example.com/", Err: err}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"remote alert", wrap(&net.OpError{Op: "remote error", Err: errors.New("tls: unrecognized name")}), true},
+		{"alert", wrap(tls.AlertError(112)), true},
+		{"record header", wrap(tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}), true},
+		{"refused", wrap(&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}), false},
+		{"timeout", wrap(context.DeadlineExceeded), false},
+		{"other", wrap(fmt.Errorf("unexpected EOF")), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := handshakeRejected(tt.err); got != tt.want {
+				t.Errorf("handshakeRejected(%v) = %v; want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSNIMismatchReject(t *testing.T) {
+	tests := []struct {
+		name    string
+		refuse  bool
+		handler func(w http.ResponseWriter, r *http.Request)
+		want    string
+	}{
+		{"handshake refused", true, nil, ""},
+		{"misdirected", false, func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS.ServerName != "" {
+				w.WriteHeader(http.StatusMisdirectedRequest)
+			}
+		}, ""},
+		{"served", false, func(w http.ResponseWriter, r *http.Request) {}, "expected the mismatched request to be rejected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			if tt.handler != nil {
+				handler = tt.handler
+			}
+
+			server := httptest.NewUnstartedServer(handler)
+			server.TLS = &tls.Config{
+				// The url names 127.0.0.1, and no SNI is sent for IP
+				// addresses, so only the mismatched request has one.
+				GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+					if tt.refuse && hello.ServerName != "" {
+						return nil, fmt.Errorf("unknown server name %s", hello.ServerName)
+					}
+					return nil, nil
+				},
+			}
+			server.StartTLS()
+			defer server.Close()
+
+			config, _ := json.Marshal(map[string]any{
+				"url":        server.URL,
+				"match_type": "sniMismatch",
+				"sni_name":   "other.example",
+				"insecure":   true,
+			})
+
+			err := New().Run(context.Background(), string(config))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Run() = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Run() = %v; want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSNIMismatchDefaultSkipsVerification(t *testing.T) {
+	// The test certificate does not cover other.example, so the mismatched
+	// request is served only because its certificate goes unverified.
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("default site"))
+	}))
+	defer server.Close()
+
+	err := runAgainst(t, server, map[string]any{
+		"match_type":      "sniMismatch",
+		"sni_name":        "other.example",
+		"mismatch_policy": "default",
+		"expected_output": "default site",
+	})
+	checkError(t, err, "")
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// sriHashes are the Subresource Integrity algorithms from weakest to
+// strongest.
+var sriHashes = []struct {
+	name string
+	new  func() hash.Hash
+}{
+	{"sha256", sha256.New},
+	{"sha384", sha512.New384},
+	{"sha512", sha512.New},
+}
+
+// sriMatcher expects every script and stylesheet on the page that carries an
+// integrity attribute to load and match it, and at least one to exist.
+type sriMatcher struct{}
+
+func (sriMatcher) ValidateConfig(conf Schema) error {
+	return nil
+}
+
+func (sriMatcher) Match(ctx context.Context, ex *Exchange) error {
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	base := ex.Response.Request.URL
+	checked := 0
+	failures := []string{}
+
+	for _, element := range scanHTML(string(body)) {
+		attr := ""
+		switch element.Tag {
+		case "script":
+			attr = "src"
+		case "link":
+			attr = "href"
+		default:
+			continue
+		}
+
+		integrity, ok := element.Attr("integrity")
+		if !ok || strings.TrimSpace(integrity) == "" {
+			continue
+		}
+
+		raw, ok := element.Attr(attr)
+		if !ok {
+			continue
+		}
+		ref, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: invalid url", raw))
+			continue
+		}
+		target := base.ResolveReference(ref).String()
+		checked++
+
+		err = verifyIntegrity(ctx, ex, target, integrity)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", target, err))
+		}
+	}
+
+	if checked == 0 {
+		return fmt.Errorf("no script or link elements with integrity attributes found")
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d subresources failed integrity: %s", len(failures), checked, strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+// verifyIntegrity fetches target and compares it against the strongest
+// algorithm listed in integrity, as browsers do; any digest for that
+// algorithm may match.
+func verifyIntegrity(ctx context.Context, ex *Exchange, target string, integrity string) error {
+	strongest := -1
+	digests := map[int][]string{}
+
+	for _, token := range strings.Fields(integrity) {
+		token, _, _ = strings.Cut(token, "?")
+		name, digest, ok := strings.Cut(token, "-")
+		if !ok {
+			continue
+		}
+
+		for i, alg := range sriHashes {
+			if alg.name == strings.ToLower(name) {
+				digests[i] = append(digests[i], digest)
+				strongest = max(strongest, i)
+			}
+		}
+	}
+
+	if strongest < 0 {
+		return fmt.Errorf("no supported hash in integrity %q", integrity)
+	}
+
+	resp, err := ex.fetch(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer drainBody(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	h := sriHashes[strongest].new()
+	_, err = io.Copy(h, resp.Body)
+	if err != nil {
+		return fmt.Errorf("encountered error while reading response body: %v", err)
+	}
+
+	got := base64.StdEncoding.EncodeToString(h.Sum(nil))
+	for _, digest := range digests[strongest] {
+		if digest == got {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s digest %s does not match integrity", sriHashes[strongest].name, got)
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSubresourceIntegrity(t *testing.T) {
+	script := []byte("console.log('scoreboard');")
+	sum256 := sha256.Sum256(script)
+	sum512 := sha512.Sum512(script)
+	good256 := "sha256-" + base64.StdEncoding.EncodeToString(sum256[:])
+	good512 := "sha512-" + base64.StdEncoding.EncodeToString(sum512[:])
+	bad256 := "sha256-" + base64.StdEncoding.EncodeToString(make([]byte, 32))
+	bad512 := "sha512-" + base64.StdEncoding.EncodeToString(make([]byte, 64))
+
+	tests := []struct {
+		name string
+		page string
+		want string
+	}{
+		{"match", `<script src="/app.js" integrity="` + good256 + `"></script>`, ""},
+		{"stylesheet", `<link rel="stylesheet" href="/app.js" integrity="` + good512 + `">`, ""},
+		{"strongest algorithm decides", `<script src="/app.js" integrity="` + bad256 + " " + good512 + `"></script>`, ""},
+		{"weaker match ignored", `<script src="/app.js" integrity="` + good256 + " " + bad512 + `"></script>`, "sha512 digest"},
+		{"any digest of an algorithm", `<script src="/app.js" integrity="` + bad256 + " " + good256 + `"></script>`, ""},
+		{"options ignored", `<script src="/app.js" integrity="` + good256 + `?ct=application/javascript"></script>`, ""},
+		{"mismatch", `<script src="/app.js" integrity="` + bad256 + `"></script>`, "1 of 1 subresources failed integrity"},
+		{"unsupported hash", `<script src="/app.js" integrity="md5-abc"></script>`, `no supported hash in integrity "md5-abc"`},
+		{"missing subresource", `<script src="/gone.js" integrity="` + good256 + `"></script>`, "/gone.js: status 404"},
+		{"only unprotected elements", `<script src="/app.js"></script><img src="/app.js" integrity="` + good256 + `">`, "no script or link elements with integrity attributes found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/":
+					w.Write([]byte(tt.page))
+				case "/app.js":
+					w.Write(script)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			err := runAgainst(t, server, map[string]any{"match_type": "subresourceIntegrity"})
+			checkError(t, err, tt.want)
+		})
+	}
+}
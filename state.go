@@ -0,0 +1,157 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// StateStore keeps small values across runs, keyed by check target.
+type StateStore interface {
+	Load(key string) (string, bool, error)
+	Store(key string, value string) error
+}
+
+// MemoryStateStore is a StateStore that lives as long as the process.
+type MemoryStateStore struct {
+	values sync.Map
+}
+
+func (m *MemoryStateStore) Load(key string) (string, bool, error) {
+	value, ok := m.values.Load(key)
+	if !ok {
+		return "", false, nil
+	}
+
+	return value.(string), true, nil
+}
+
+func (m *MemoryStateStore) Store(key string, value string) error {
+	m.values.Store(key, value)
+	return nil
+}
+
+// FileStateStore is a StateStore backed by a JSON object in a file, so state
+// survives engine restarts.
+type FileStateStore struct {
+	Path string
+}
+
+var fileStateMu sync.Mutex
+
+func (f FileStateStore) read() (map[string]string, error) {
+	values := map[string]string{}
+
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &values)
+	if err != nil {
+		return nil, fmt.Errorf("encountered error while parsing state file: %v", err)
+	}
+
+	return values, nil
+}
+
+func (f FileStateStore) Load(key string) (string, bool, error) {
+	fileStateMu.Lock()
+	defer fileStateMu.Unlock()
+
+	values, err := f.read()
+	if err != nil {
+		return "", false, err
+	}
+
+	value, ok := values[key]
+	return value, ok, nil
+}
+
+func (f FileStateStore) Store(key string, value string) error {
+	fileStateMu.Lock()
+	defer fileStateMu.Unlock()
+
+	values, err := f.read()
+	if err != nil {
+		return err
+	}
+	values[key] = value
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	tmp := f.Path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, f.Path)
+}
+
+var defaultState = &MemoryStateStore{}
+
+func (c *Checker) stateStore(conf Schema) StateStore {
+	switch {
+	case c.state != nil:
+		return c.state
+	case conf.StateFile != "":
+		return FileStateStore{Path: conf.StateFile}
+	default:
+		return defaultState
+	}
+}
+
+// detectChange compares the body hash with the one recorded for the same
+// target on an earlier passing run, then records the new hash.
+func (c *Checker) detectChange(ex *Exchange) error {
+	if ex.Config.ChangeDetection == "off" {
+		return nil
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+	key := "content:" + ex.Request.Method + " " + redact(ex.Config, ex.Request.URL.Redacted())
+
+	store := c.stateStore(ex.Config)
+
+	previous, seen, err := store.Load(key)
+	if err != nil {
+		return fmt.Errorf("encountered error while loading change detection state: %v", err)
+	}
+
+	changed := seen && previous != hash
+
+	// In fail mode the baseline is kept, so a defacement keeps failing until
+	// the original content is restored.
+	if changed && ex.Config.ChangeDetection == "fail" {
+		return fmt.Errorf("response body changed since the baseline run; sha256 %.12s -> %.12s", previous, hash)
+	}
+
+	err = store.Store(key, hash)
+	if err != nil {
+		return fmt.Errorf("encountered error while saving change detection state: %v", err)
+	}
+
+	if changed {
+		ex.Result.Note("response body changed since the last run; sha256 %.12s -> %.12s", previous, hash)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// splitLines splits a one-entry-per-line field, dropping blank lines. Entries
+// are kept verbatim apart from a trailing carriage return.
+func splitLines(raw string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+func assertForbiddenStrings(ctx context.Context, ex *Exchange) error {
+	forbidden := splitLines(ex.Config.ForbiddenStrings)
+	if len(forbidden) == 0 {
+		return nil
+	}
+
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range forbidden {
+		if strings.Contains(string(body), s) {
+			return fmt.Errorf("forbidden string found in response body: %q", s)
+		}
+	}
+
+	return nil
+}
+
+// matchAllSubstrings expects every line of expected_output to appear in the
+// body.
+func matchAllSubstrings(ctx context.Context, ex *Exchange) error {
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	missing := []string{}
+	for _, s := range splitLines(ex.Config.ExpectedOutput) {
+		if !strings.Contains(string(body), s) {
+			missing = append(missing, strconv.Quote(s))
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("expected output not found in response body: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// matchAnySubstring expects at least one line of expected_output to appear
+// in the body.
+func matchAnySubstring(ctx context.Context, ex *Exchange) error {
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range splitLines(ex.Config.ExpectedOutput) {
+		if strings.Contains(string(body), s) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("none of the expected outputs found in response body")
+}
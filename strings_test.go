@@ -0,0 +1,125 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []string
+	}{
+		{"a\nb", []string{"a", "b"}},
+		{"a\r\nb\r\n", []string{"a", "b"}},
+		{"a\n\n  \nb", []string{"a", "b"}},
+		{" padded \n\ttab", []string{" padded ", "\ttab"}},
+		{"", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := splitLines(tt.raw); !slices.Equal(got, tt.want) {
+				t.Errorf("splitLines(%q) = %q; want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForbiddenStrings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Welcome\nWarning: mysql_connect(): Access denied"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		forbidden string
+		want      string
+	}{
+		{"none found", "Traceback\nFatal error", ""},
+		{"one found", "Traceback\nmysql_connect()", `forbidden string found in response body: "mysql_connect()"`},
+		{"case-sensitive", "warning:", ""},
+		{"blank lines ignored", "\n\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runAgainst(t, server, map[string]any{
+				"match_type":        "substringMatch",
+				"expected_output":   "Welcome",
+				"forbidden_strings": tt.forbidden,
+			})
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestForbiddenStringsAfterMatchFails(t *testing.T) {
+	// The match runs first, so its error is the one reported.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Traceback"))
+	}))
+	defer server.Close()
+
+	err := runAgainst(t, server, map[string]any{
+		"match_type":        "substringMatch",
+		"expected_output":   "Welcome",
+		"forbidden_strings": "Traceback",
+	})
+	checkError(t, err, "expected output not found")
+}
+
+func TestAllSubstrings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Welcome to the scoreboard, version 1.2"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		expected string
+		want     string
+	}{
+		{"all present", "Welcome\nscoreboard", ""},
+		{"order ignored", "version\nWelcome", ""},
+		{"spaces kept", "the scoreboard", ""},
+		{"every missing one listed", "Welcome\nflag\ntoken", `expected output not found in response body: "flag", "token"`},
+		{"trailing space kept", "scoreboard ", `expected output not found in response body: "scoreboard "`},
+		{"CRLF lines", "Welcome\r\nversion 1.2\r\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runAgainst(t, server, map[string]any{"match_type": "allSubstrings", "expected_output": tt.expected})
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestAnySubstring(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Service: degraded"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		expected string
+		want     string
+	}{
+		{"first line", "degraded\nok", ""},
+		{"later line", "ok\nhealthy\ndegraded", ""},
+		{"none", "ok\nhealthy", "none of the expected outputs found in response body"},
+		{"case-sensitive", "DEGRADED", "none of the expected outputs found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runAgainst(t, server, map[string]any{"match_type": "anySubstring", "expected_output": tt.expected})
+			checkError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type targetKey struct{}
+
+// WithTarget attaches a team's target metadata, as injected by the scoring
+// engine, to ctx. Each value fills the matching {{.name}} placeholder in url,
+// and a base_url value resolves relative urls, so one config can score every
+// team:
+//
+//	url: {{.scheme}}://{{.host}}:8080/health
+//	url: /health
+func WithTarget(ctx context.Context, vars map[string]string) context.Context {
+	merged := map[string]string{}
+	if parent, ok := ctx.Value(targetKey{}).(map[string]string); ok {
+		maps.Copy(merged, parent)
+	}
+	maps.Copy(merged, vars)
+
+	return context.WithValue(ctx, targetKey{}, merged)
+}
+
+// resolveURL fills the placeholders in raw, which may list several URLs, as
+// of now, and resolves relative URLs against the target's base_url.
+func resolveURL(ctx context.Context, raw string, now time.Time) (string, error) {
+	raw, err := render(ctx, "url", raw, now)
+	if err != nil {
+		return "", err
+	}
+
+	vars, _ := ctx.Value(targetKey{}).(map[string]string)
+	base, ok := vars["base_url"]
+	if !ok {
+		return raw, nil
+	}
+
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid target base_url provided: %v; %q", base, err)
+	}
+
+	lines := splitLines(raw)
+	for i, line := range lines {
+		ref, err := url.Parse(strings.TrimSpace(line))
+		if err != nil || ref.IsAbs() {
+			continue
+		}
+		lines[i] = baseURL.ResolveReference(ref).String()
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResolveURL(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	team := map[string]string{"scheme": "https", "host": "10.0.5.2"}
+	based := map[string]string{"base_url": "http://10.0.5.2:8080/app/"}
+
+	tests := []struct {
+		name string
+		vars map[string]string
+		raw  string
+		want string
+		err  string
+	}{
+		{"no target", nil, "http://example.com/", "http://example.com/", ""},
+		{"placeholders", team, "{{.scheme}}://{{.host}}:8443/health", "https://10.0.5.2:8443/health", ""},
+		{"relative", based, "health?full=1", "http://10.0.5.2:8080/app/health?full=1", ""},
+		{"root-relative", based, "/health", "http://10.0.5.2:8080/health", ""},
+		{"absolute kept", based, "http://other.example/", "http://other.example/", ""},
+		{"each line", based, "/a\nhttp://other.example/b\n  c  ", "http://10.0.5.2:8080/a\nhttp://other.example/b\nhttp://10.0.5.2:8080/app/c", ""},
+		{"relative without base", team, "/health", "/health", ""},
+		{"missing variable", team, "http://{{.team}}.example/", "", "encounted error while filling url template"},
+		{"invalid base", map[string]string{"base_url": "http://[::1"}, "/health", "", "invalid target base_url provided"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.vars != nil {
+				ctx = WithTarget(ctx, tt.vars)
+			}
+
+			got, err := resolveURL(ctx, tt.raw, now)
+			checkError(t, err, tt.err)
+			if tt.err == "" && got != tt.want {
+				t.Errorf("resolveURL(%q) = %q; want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithTargetMerges(t *testing.T) {
+	ctx := WithTarget(context.Background(), map[string]string{"host": "a", "team": "blue"})
+	ctx = WithTarget(ctx, map[string]string{"host": "b"})
+
+	got, err := resolveURL(ctx, "http://{{.host}}/{{.team}}", time.Now())
+	checkError(t, err, "")
+	if got != "http://b/blue" {
+		t.Errorf("resolveURL() = %q; want the later host and the earlier team", got)
+	}
+}
+
+func TestRunWithTarget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("team " + r.URL.Query().Get("team")))
+	}))
+	defer server.Close()
+
+	ctx := WithTarget(context.Background(), map[string]string{
+		"base_url": server.URL,
+		"team":     "blue",
+	})
+	config := `{"url": "/?team={{.team}}", "match_type": "exactMatch", "expected_output": "team blue"}`
+
+	checkError(t, Run(ctx, config), "")
+}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// targets returns the URLs listed one per line in url.
+func targets(conf Schema) []string {
+	urls := []string{}
+	for _, line := range splitLines(conf.URL) {
+		urls = append(urls, strings.TrimSpace(line))
+	}
+
+	return urls
+}
+
+// runTargets runs the check against every URL in a multi-URL config as
+// url_policy directs: all must pass, any one must pass, or round_robin checks
+// a single URL per run, rotating through the list.
+func (c *Checker) runTargets(ctx context.Context, conf Schema, res *Result) error {
+	urls := targets(conf)
+	if len(urls) == 1 {
+		conf.URL = urls[0]
+		return c.runMode(ctx, conf, res)
+	}
+
+	if conf.URLPolicy == "round_robin" {
+		conf.URL = urls[c.nextTarget(conf.URL, len(urls))]
+		res.Note("checked %s", conf.URL)
+		return c.runMode(ctx, conf, res)
+	}
+
+	failures := []string{}
+	for _, target := range urls {
+		target_conf := conf
+		target_conf.URL = target
+
+		err := c.runMode(ctx, target_conf, res)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", target, err))
+			continue
+		}
+
+		if conf.URLPolicy == "any" {
+			res.Note("passed on %s", target)
+			return nil
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	if conf.URLPolicy == "any" {
+		return fmt.Errorf("all %d urls failed: %s", len(urls), strings.Join(failures, "; "))
+	}
+
+	return fmt.Errorf("%d of %d urls failed: %s", len(failures), len(urls), strings.Join(failures, "; "))
+}
+
+// nextTarget returns the index of the URL a round_robin config checks next.
+// The position is kept per url list for the life of the Checker.
+func (c *Checker) nextTarget(list string, n int) int {
+	c.cursorsMu.Lock()
+	defer c.cursorsMu.Unlock()
+
+	if c.cursors == nil {
+		c.cursors = map[string]int{}
+	}
+
+	i := c.cursors[list] % n
+	c.cursors[list] = i + 1
+
+	return i
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTargets(t *testing.T) {
+	conf := Schema{URL: " http://a.example/ \r\n\nhttp://b.example/\n  \n"}
+
+	got := targets(conf)
+	want := []string{"http://a.example/", "http://b.example/"}
+	if !slices.Equal(got, want) {
+		t.Errorf("targets() = %q; want %q", got, want)
+	}
+}
+
+func TestURLPolicy(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer up.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	tests := []struct {
+		name   string
+		urls   []string
+		policy string
+		want   string
+	}{
+		{"all pass", []string{up.URL, up.URL + "/b"}, "all", ""},
+		{"all with one down", []string{up.URL, down.URL}, "all", "1 of 2 urls failed: " + down.URL + ": expected status code: 200; got: 503"},
+		{"any with one down", []string{down.URL, up.URL}, "any", ""},
+		{"any with all down", []string{down.URL, down.URL + "/b"}, "any", "all 2 urls failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, _ := json.Marshal(map[string]any{
+				"url":             strings.Join(tt.urls, "\n"),
+				"url_policy":      tt.policy,
+				"expected_output": "200",
+			})
+
+			err := New().Run(context.Background(), string(config))
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestURLPolicyAnyStopsAtFirstPass(t *testing.T) {
+	var mu sync.Mutex
+	paths := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	config, _ := json.Marshal(map[string]any{
+		"url":             server.URL + "/a\n" + server.URL + "/b",
+		"url_policy":      "any",
+		"expected_output": "200",
+	})
+
+	res, err := New().Check(context.Background(), string(config))
+	checkError(t, err, "")
+
+	if !slices.Equal(paths, []string{"/a"}) {
+		t.Errorf("requested %q; want only /a", paths)
+	}
+	if !slices.Equal(res.Notes, []string{"passed on " + server.URL + "/a"}) {
+		t.Errorf("Notes = %q", res.Notes)
+	}
+}
+
+func TestURLPolicyRoundRobin(t *testing.T) {
+	var mu sync.Mutex
+	paths := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	config := func(urls ...string) string {
+		for i, u := range urls {
+			urls[i] = server.URL + u
+		}
+		doc, _ := json.Marshal(map[string]any{
+			"url":             strings.Join(urls, "\n"),
+			"url_policy":      "round_robin",
+			"expected_output": "200",
+		})
+		return string(doc)
+	}
+
+	// Each url list keeps its own position, for the life of the Checker.
+	checker := New()
+	for _, doc := range []string{
+		config("/a", "/b", "/c"),
+		config("/a", "/b", "/c"),
+		config("/x", "/y"),
+		config("/a", "/b", "/c"),
+		config("/a", "/b", "/c"),
+	} {
+		checkError(t, checker.Run(context.Background(), doc), "")
+	}
+	checkError(t, New().Run(context.Background(), config("/a", "/b", "/c")), "")
+
+	want := []string{"/a", "/b", "/x", "/c", "/a", "/a"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("requested %q; want %q", paths, want)
+	}
+}
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// templateFuncs are the helpers available in url, body and header templates,
+// for APIs that reject stale or replayed requests:
+//
+//	{{timestamp}}    unix seconds
+//	{{timestampMs}}  unix milliseconds
+//	{{rfc3339}}      2006-01-02T15:04:05Z
+//	{{httpDate}}     Mon, 02 Jan 2006 15:04:05 GMT
+//	{{now.Format "2006-01-02"}}
+//	{{nonce}}        32 random hex characters, fresh on every call
+//
+// Every time helper in one request reports the same instant, now.
+func templateFuncs(now time.Time) template.FuncMap {
+	now = now.UTC()
+
+	return template.FuncMap{
+		"now":         func() time.Time { return now },
+		"timestamp":   func() string { return strconv.FormatInt(now.Unix(), 10) },
+		"timestampMs": func() string { return strconv.FormatInt(now.UnixMilli(), 10) },
+		"rfc3339":     func() string { return now.Format(time.RFC3339) },
+		"httpDate":    func() string { return now.Format(http.TimeFormat) },
+		"nonce":       nonce,
+	}
+}
+
+// parseTemplate parses raw with the template helpers available.
+func parseTemplate(name string, raw string) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(templateFuncs(time.Time{})).Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template provided: %v; %q", name, raw, err)
+	}
+
+	return tmpl, nil
+}
+
+// render fills the {{ }} actions in raw from the target attached to ctx and
+// the template helpers, evaluated at now. Strings without actions are
+// returned as they are.
+func render(ctx context.Context, name string, raw string, now time.Time) (string, error) {
+	if !strings.Contains(raw, "{{") {
+		return raw, nil
+	}
+
+	tmpl, err := parseTemplate(name, raw)
+	if err != nil {
+		return "", err
+	}
+
+	vars, _ := ctx.Value(targetKey{}).(map[string]string)
+	if vars == nil {
+		vars = map[string]string{}
+	}
+
+	var expanded strings.Builder
+	err = tmpl.Funcs(templateFuncs(now)).Execute(&expanded, vars)
+	if err != nil {
+		return "", fmt.Errorf("encounted error while filling %s template: %v", name, err)
+	}
+
+	return expanded.String(), nil
+}
+
+// perRequest reports whether raw calls a template helper, such as nonce or
+// timestamp, whose value must be fresh for every request.
+func perRequest(raw string) bool {
+	if !strings.Contains(raw, "{{") {
+		return false
+	}
+
+	tmpl, err := parseTemplate("url", raw)
+	if err != nil {
+		return false
+	}
+
+	return callsHelper(tmpl.Tree.Root)
+}
+
+func callsHelper(node parse.Node) bool {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return false
+		}
+		for _, child := range node.Nodes {
+			if callsHelper(child) {
+				return true
+			}
+		}
+	case *parse.ActionNode:
+		return callsHelper(node.Pipe)
+	case *parse.PipeNode:
+		if node == nil {
+			return false
+		}
+		for _, cmd := range node.Cmds {
+			if callsHelper(cmd) {
+				return true
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range node.Args {
+			if callsHelper(arg) {
+				return true
+			}
+		}
+	case *parse.ChainNode:
+		return callsHelper(node.Node)
+	case *parse.IdentifierNode:
+		_, ok := templateFuncs(time.Time{})[node.Ident]
+		return ok
+	case *parse.IfNode:
+		return callsHelper(node.Pipe) || callsHelper(node.List) || callsHelper(node.ElseList)
+	case *parse.RangeNode:
+		return callsHelper(node.Pipe) || callsHelper(node.List) || callsHelper(node.ElseList)
+	case *parse.WithNode:
+		return callsHelper(node.Pipe) || callsHelper(node.List) || callsHelper(node.ElseList)
+	}
+
+	return false
+}
+
+// renderRequest fills the templates in the url, body and headers of conf. It
+// runs for every request built, so retries and follow-ups get a fresh
+// timestamp and nonce. A generated body, such as uploadIntegrity's random
+// payload, is data rather than a template and is sent as it is.
+func renderRequest(ctx context.Context, conf Schema, now time.Time) (Schema, error) {
+	var err error
+
+	conf.URL, err = resolveURL(ctx, conf.URL, now)
+	if err != nil {
+		return conf, err
+	}
+
+	if !generatedBody(conf) {
+		conf.Body, err = render(ctx, "body", conf.Body, now)
+		if err != nil {
+			return conf, err
+		}
+	}
+
+	conf.Headers, err = render(ctx, "headers", conf.Headers, now)
+	if err != nil {
+		return conf, err
+	}
+
+	conf.RequestHeaders, err = render(ctx, "request_headers", conf.RequestHeaders, now)
+	if err != nil {
+		return conf, err
+	}
+
+	return conf, nil
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	ctx := WithTarget(context.Background(), map[string]string{"team": "blue"})
+
+	tests := []struct {
+		name string
+		raw  string
+		want string
+		err  string
+	}{
+		{"no actions", "plain {text}", "plain {text}", ""},
+		{"target", "team={{.team}}", "team=blue", ""},
+		{"timestamp", "{{timestamp}}", "1714979289", ""},
+		{"timestampMs", "{{timestampMs}}", "1714979289000", ""},
+		{"rfc3339", "{{rfc3339}}", "2024-05-06T07:08:09Z", ""},
+		{"httpDate", "{{httpDate}}", "Mon, 06 May 2024 07:08:09 GMT", ""},
+		{"now format", `{{now.Format "2006-01-02"}}`, "2024-05-06", ""},
+		{"missing key", "{{.round}}", "", "encounted error while filling body template"},
+		{"parse error", "{{", "", "invalid body template provided"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := render(ctx, "body", tt.raw, now)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("render(%q) = %v; want error containing %q", tt.raw, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("render(%q) = %v", tt.raw, err)
+			}
+			if got != tt.want {
+				t.Errorf("render(%q) = %q; want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderNonce(t *testing.T) {
+	got, err := render(context.Background(), "body", "{{nonce}} {{nonce}}", time.Now())
+	if err != nil {
+		t.Fatalf("render() = %v", err)
+	}
+
+	a, b, _ := strings.Cut(got, " ")
+	if len(a) != 32 || a == b {
+		t.Errorf("render({{nonce}} {{nonce}}) = %q; want two different 32 character nonces", got)
+	}
+}
+
+func TestRenderRequestGeneratedBody(t *testing.T) {
+	conf := Schema{MatchType: "uploadIntegrity", Body: "\x00{{ not a template"}
+
+	got, err := renderRequest(context.Background(), conf, time.Now())
+	if err != nil {
+		t.Fatalf("renderRequest() = %v", err)
+	}
+	if got.Body != conf.Body {
+		t.Errorf("generated body was rendered: %q", got.Body)
+	}
+}
+
+func TestPerRequest(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want bool
+	}{
+		{"http://example.com/", false},
+		{"http://{{.host}}/", false},
+		{"http://example.com/?n={{nonce}}", true},
+		{"http://example.com/?t={{timestamp}}", true},
+		{`http://example.com/{{now.Format "2006"}}/`, true},
+		{"http://example.com/{{if .team}}{{nonce}}{{end}}", true},
+		{"http://example.com/{{with .team}}{{.}}{{end}}", false},
+		{"http://example.com/{{", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := perRequest(tt.raw); got != tt.want {
+				t.Errorf("perRequest(%q) = %v; want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLRenderedPerRequest(t *testing.T) {
+	nonces := make(chan string, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonces <- r.URL.Query().Get("n")
+	}))
+	defer server.Close()
+
+	ctx := WithTarget(context.Background(), map[string]string{"base_url": server.URL})
+	config := `{"url": "/?n={{nonce}}", "expected_output": "200", "mode": "consecutive", "consecutive_successes": 3, "attempt_delay_ms": 0}`
+
+	err := Run(ctx, config)
+	if err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+	close(nonces)
+
+	seen := map[string]bool{}
+	for n := range nonces {
+		if len(n) != 32 || seen[n] {
+			t.Errorf("attempt sent nonce %q; want a fresh one each attempt", n)
+		}
+		seen[n] = true
+	}
+}
@@ -0,0 +1,189 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Timings are the phase durations of one request, measured with httptrace.
+// Phases that did not happen, such as DNS and the handshakes on a reused
+// connection, are zero. TTFB and Total count from the start of the request;
+// Total runs until the body was read, or to the first byte if it never was,
+// or until the request failed if no response arrived.
+type Timings struct {
+	DNS          time.Duration `json:"dns"`
+	Connect      time.Duration `json:"connect"`
+	TLSHandshake time.Duration `json:"tls_handshake"`
+	TTFB         time.Duration `json:"ttfb"`
+	Total        time.Duration `json:"total"`
+	Reused       bool          `json:"reused"`
+}
+
+func (t Timings) String() string {
+	phases := []string{}
+	for _, phase := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"dns", t.DNS},
+		{"connect", t.Connect},
+		{"tls", t.TLSHandshake},
+		{"ttfb", t.TTFB},
+		{"total", t.Total},
+	} {
+		if phase.value > 0 {
+			phases = append(phases, fmt.Sprintf("%s=%v", phase.name, phase.value.Round(time.Millisecond)))
+		}
+	}
+
+	if t.Reused {
+		phases = append(phases, "reused")
+	}
+
+	if len(phases) == 0 {
+		return "none"
+	}
+
+	return strings.Join(phases, " ")
+}
+
+// tracer records Timings for a request. Trace hooks may run on transport
+// goroutines, hence the lock.
+type tracer struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	firstByte    time.Time
+	bodyDone     time.Time
+	failedAt     time.Time
+	interim      []interimResponse
+	timings      Timings
+}
+
+// attach returns req with a trace that records into t.
+func (t *tracer) attach(req *http.Request) *http.Request {
+	t.mu.Lock()
+	t.start = time.Now()
+	t.mu.Unlock()
+
+	record := func(fn func()) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		fn()
+	}
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			record(func() { t.timings.Reused = info.Reused })
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			record(func() { t.dnsStart = time.Now() })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			record(func() { t.timings.DNS = time.Since(t.dnsStart) })
+		},
+		ConnectStart: func(network, addr string) {
+			record(func() { t.connectStart = time.Now() })
+		},
+		ConnectDone: func(network, addr string, err error) {
+			record(func() { t.timings.Connect = time.Since(t.connectStart) })
+		},
+		TLSHandshakeStart: func() {
+			record(func() { t.tlsStart = time.Now() })
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			record(func() { t.timings.TLSHandshake = time.Since(t.tlsStart) })
+		},
+		GotFirstResponseByte: func() {
+			record(func() { t.firstByte = time.Now() })
+		},
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			record(func() { t.interim = append(t.interim, interimResponse{code: code, header: http.Header(header)}) })
+			return nil
+		},
+	}
+
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
+
+// bodyRead marks the moment the response body was fully read.
+func (t *tracer) bodyRead() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.bodyDone.IsZero() {
+		t.bodyDone = time.Now()
+	}
+}
+
+// failed marks the moment the request gave up without a response.
+func (t *tracer) failed() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.failedAt = time.Now()
+}
+
+func (t *tracer) snapshot() Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	timings := t.timings
+
+	if !t.firstByte.IsZero() {
+		timings.TTFB = t.firstByte.Sub(t.start)
+		timings.Total = timings.TTFB
+	}
+
+	if !t.bodyDone.IsZero() {
+		timings.Total = t.bodyDone.Sub(t.start)
+	}
+
+	if timings.Total == 0 && !t.failedAt.IsZero() {
+		timings.Total = t.failedAt.Sub(t.start)
+	}
+
+	return timings
+}
+
+func observeTimings(ex *Exchange) {
+	recordTimings(ex.Result, ex.Timings())
+}
+
+// recordTimings stores the timings of the latest request in res, whether or
+// not it got a response.
+func recordTimings(res *Result, timings Timings) {
+	res.update(func(r *Result) {
+		r.Timings = timings
+		r.LatencyMs = timings.Total.Milliseconds()
+	})
+}
+
+func assertTLSHandshake(ctx context.Context, ex *Exchange) error {
+	if ex.Config.MaxTLSHandshakeMs <= 0 {
+		return nil
+	}
+
+	if ex.Response.TLS == nil {
+		return fmt.Errorf("max_tls_handshake_ms requires an https url")
+	}
+
+	took := ex.Timings().TLSHandshake
+
+	limit := time.Duration(ex.Config.MaxTLSHandshakeMs) * time.Millisecond
+	if took > limit {
+		return fmt.Errorf("TLS handshake took %v; limit %dms", took.Round(time.Millisecond), ex.Config.MaxTLSHandshakeMs)
+	}
+
+	return nil
+}
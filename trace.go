@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// traceDisabledMatcher sends TRACE and TRACK carrying a random marker header
+// and expects both to be refused without the marker being echoed back.
+type traceDisabledMatcher struct{}
+
+func (traceDisabledMatcher) ValidateConfig(conf Schema) error {
+	_, err := rejectStatuses(conf, nil)
+	return err
+}
+
+func (traceDisabledMatcher) Match(ctx context.Context, ex *Exchange) error {
+	allowed, err := rejectStatuses(ex.Config, []int{http.StatusMethodNotAllowed, http.StatusNotImplemented})
+	if err != nil {
+		return err
+	}
+
+	marker, err := nonce()
+	if err != nil {
+		return err
+	}
+
+	for _, method := range []string{http.MethodTrace, "TRACK"} {
+		req, err := ex.probeRequest(ctx, method, false)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("X-Scorify-Trace", marker)
+
+		resp, err := ex.Client.Do(req)
+		if err != nil {
+			return fmt.Errorf("encounted error while making %s request: %v", method, err.Error())
+		}
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		drainBody(resp.Body)
+		if err != nil {
+			return fmt.Errorf("encountered error while reading %s response body: %v", method, err)
+		}
+
+		if strings.Contains(string(body), marker) {
+			return fmt.Errorf("%s echoed the request back in the response body", method)
+		}
+
+		if !slices.Contains(allowed, resp.StatusCode) {
+			return fmt.Errorf("expected %s to be refused with one of %v; got: %d", method, allowed, resp.StatusCode)
+		}
+	}
+
+	return nil
+}
+
+// nonce returns 16 random bytes, hex encoded.
+func nonce() (string, error) {
+	buf := make([]byte, 16)
+
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", fmt.Errorf("encountered error while generating nonce: %v", err)
+	}
+
+	return hex.EncodeToString(buf), nil
+}
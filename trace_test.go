@@ -0,0 +1,60 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTraceDisabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter, r *http.Request)
+		conf    map[string]any
+		want    string
+	}{
+		{"refused", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}, nil, ""},
+		{"track allowed", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "TRACK" {
+				return
+			}
+			w.WriteHeader(http.StatusNotImplemented)
+		}, nil, "expected TRACK to be refused"},
+		{"echoed despite refusal", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			r.Header.Write(w)
+		}, nil, "TRACE echoed the request back"},
+		{"custom statuses", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}, map[string]any{"reject_statuses": "403"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Probes without the check's auth and headers are turned away.
+				if r.URL.Path != "/app" || r.Header.Get("Authorization") != "Bearer t0k3n" || r.Header.Get("X-Team") != "blue" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				tt.handler(w, r)
+			}))
+			defer server.Close()
+
+			conf := map[string]any{
+				"url":             server.URL + "/app",
+				"match_type":      "traceDisabled",
+				"auth":            "bearer",
+				"auth_token":      "t0k3n",
+				"request_headers": "X-Team: blue",
+			}
+			for key, value := range tt.conf {
+				conf[key] = value
+			}
+
+			checkError(t, runAgainst(t, server, conf), tt.want)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// sendTunnel treats url as a proxy under test: it opens a CONNECT tunnel
+// through it to tunnel_url's host and sends the configured request for
+// tunnel_url inside, with tunnel_verb as its method. The exchange holds the
+// inner request and response, so every matcher applies to what came back
+// through the tunnel, and its client opens a tunnel for each follow-up
+// request.
+func (c *Checker) sendTunnel(ctx context.Context, conf Schema, res *Result) (*Exchange, error) {
+	proxy, err := url.Parse(conf.URL)
+	if err != nil {
+		return nil, fmt.Errorf("encounted error while creating request: %v", err.Error())
+	}
+
+	inner := conf
+	inner.URL = conf.TunnelURL
+	inner.Verb = conf.TunnelVerb
+
+	req, _, err := prepareRequest(ctx, inner)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := tunnelDialer{Dialer: c.pool.dialerFor(keyFor(conf)), proxy: proxy, insecure: conf.Insecure}
+	client, err := withTunnel(c.httpClient(inner), dialer)
+	if err != nil {
+		return nil, err
+	}
+
+	trace := &tracer{}
+	resp, err := client.Do(trace.attach(req))
+	if err != nil {
+		// The proxy answered, so this is its policy rather than a failure to
+		// reach it; retrying or falling back would not change the answer.
+		var refused *proxyRefusedError
+		if errors.As(err, &refused) {
+			return nil, refused
+		}
+		return nil, failedRequest(err, trace)
+	}
+
+	body, err := requestBody(req)
+	if err != nil {
+		drainBody(resp.Body)
+		return nil, err
+	}
+
+	return &Exchange{
+		Config:      conf,
+		Request:     req,
+		Response:    resp,
+		Client:      client,
+		Result:      res,
+		RequestBody: body,
+		tracer:      trace,
+	}, nil
+}
+
+// proxyRefusedError is a proxy answering CONNECT with anything but 200.
+type proxyRefusedError struct {
+	address string
+	status  int
+}
+
+func (e *proxyRefusedError) Error() string {
+	return fmt.Sprintf("proxy refused CONNECT to %s with status %d", e.address, e.status)
+}
+
+// tunnelDialer connects to any address through a CONNECT tunnel opened by
+// the proxy at proxy, reached with Dialer.
+type tunnelDialer struct {
+	Dialer
+	proxy    *url.URL
+	insecure bool
+}
+
+func (d tunnelDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := d.Dialer.DialContext(ctx, network, hostPort(d.proxy))
+	if err != nil {
+		return nil, err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if d.proxy.Scheme == "https" {
+		proxyTLS := tls.Client(conn, &tls.Config{ServerName: d.proxy.Hostname(), InsecureSkipVerify: d.insecure})
+		err = proxyTLS.HandshakeContext(ctx)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = proxyTLS
+	}
+
+	connect := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: http.Header{},
+	}
+	err = connect.Write(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// A successful CONNECT response has no body; the tunnel follows it.
+	reader := bufio.NewReader(conn)
+	established, err := http.ReadResponse(reader, connect)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if established.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, &proxyRefusedError{address: address, status: established.StatusCode}
+	}
+
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+
+	return conn, nil
+}
+
+// bufferedConn reads what was buffered past the CONNECT response before
+// reading the connection itself.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// withTunnel returns a copy of client that reaches every host through
+// dialer's tunnel, speaking TLS inside it with the client's own settings.
+// Its connections are not pooled. Only an *http.Transport can be given a
+// dialer; other transports are an error.
+func withTunnel(client *http.Client, dialer tunnelDialer) (*http.Client, error) {
+	transport, ok := client.Transport.(*http.Transport)
+	if client.Transport == nil {
+		transport, ok = http.DefaultTransport.(*http.Transport)
+	}
+	if !ok {
+		return nil, fmt.Errorf("cannot tunnel through CONNECT with a client with transport %T", client.Transport)
+	}
+
+	transport = transport.Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	transport.DisableKeepAlives = true
+
+	tunneled := *client
+	tunneled.Transport = transport
+
+	return &tunneled, nil
+}
+
+// hostPort returns u's host with the scheme's default port filled in.
+func hostPort(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	return net.JoinHostPort(u.Hostname(), port)
+}
@@ -0,0 +1,167 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// connectProxy tunnels CONNECT requests for target.test to upstream and
+// answers every CONNECT with status when it is set.
+type connectProxy struct {
+	upstream string
+	status   int
+
+	mu       sync.Mutex
+	connects int
+}
+
+func (p *connectProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	p.connects++
+	p.mu.Unlock()
+
+	if r.Method != http.MethodConnect {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if p.status != 0 {
+		w.WriteHeader(p.status)
+		return
+	}
+
+	upstream, err := net.Dial("tcp", p.upstream)
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	defer upstream.Close()
+
+	w.WriteHeader(http.StatusOK)
+	conn, buf, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	go io.Copy(upstream, buf)
+	io.Copy(conn, upstream)
+}
+
+func TestTunnel(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/style.css" {
+			w.Write([]byte("body {}"))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><link rel="stylesheet" href="/style.css"></head></html>`))
+	}))
+	defer target.Close()
+
+	tests := []struct {
+		name     string
+		status   int
+		connects int
+		want     string
+	}{
+		// The asset is fetched through a second tunnel; target.test does not
+		// resolve, so it cannot be reached directly.
+		{"follow-up tunneled", 0, 2, ""},
+		// A refusal is the proxy's answer: not retried, and not sent to
+		// fallback_url.
+		{"refused", http.StatusForbidden, 1, "proxy refused CONNECT to target.test:80 with status 403"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := &connectProxy{upstream: strings.TrimPrefix(target.URL, "http://"), status: tt.status}
+			server := httptest.NewServer(proxy)
+			defer server.Close()
+
+			config, _ := json.Marshal(map[string]any{
+				"url":             server.URL,
+				"verb":            "CONNECT",
+				"tunnel_url":      "http://target.test/",
+				"expected_output": "200",
+				"asset_samples":   1,
+				"retries":         2,
+				"fallback_url":    server.URL,
+			})
+
+			err := New().Run(context.Background(), string(config))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Run() = %v; want nil", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Run() = %v; want error containing %q", err, tt.want)
+			}
+
+			proxy.mu.Lock()
+			defer proxy.mu.Unlock()
+			if proxy.connects != tt.connects {
+				t.Errorf("proxy saw %d CONNECT requests; want %d", proxy.connects, tt.connects)
+			}
+		})
+	}
+}
+
+func TestTunnelInnerRequest(t *testing.T) {
+	target := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write([]byte(r.Method + " " + r.Header.Get("X-Team") + " " + string(body)))
+	}))
+	defer target.Close()
+
+	tests := []struct {
+		name   string
+		client *http.Client
+		want   string
+	}{
+		// The injected client trusts the target's certificate; the tunnel
+		// must verify with it rather than a default client.
+		{"injected client", target.Client(), ""},
+		{"injected transport", &http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)}, "cannot tunnel through CONNECT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := &connectProxy{upstream: strings.TrimPrefix(target.URL, "https://")}
+			server := httptest.NewServer(proxy)
+			defer server.Close()
+
+			config, _ := json.Marshal(map[string]any{
+				"url":             server.URL,
+				"verb":            "CONNECT",
+				"tunnel_url":      "https://example.com/",
+				"tunnel_verb":     "POST",
+				"headers":         "X-Team:blue",
+				"content_type":    "plain/text",
+				"body":            "hello",
+				"match_type":      "exactMatch",
+				"expected_output": "POST blue hello",
+			})
+
+			err := New(WithClient(tt.client)).Run(context.Background(), string(config))
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestValidateTunnelVerb(t *testing.T) {
+	config, _ := json.Marshal(map[string]any{
+		"url":         "http://proxy.example:3128",
+		"verb":        "CONNECT",
+		"tunnel_url":  "http://target.example/",
+		"tunnel_verb": "TRACE",
+	})
+
+	checkError(t, Validate(string(config)), "invalid tunnel_verb provided: TRACE")
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// maxUploadSize bounds upload_size so a typo cannot push gigabytes at a team.
+const maxUploadSize = 64 << 20
+
+// uploadRequest gives an uploadIntegrity config a fresh random payload to
+// send, so a service cannot pass by serving a stored copy.
+func uploadRequest(conf Schema) Schema {
+	payload := make([]byte, conf.UploadSize)
+	rand.Read(payload)
+
+	conf.Body = string(payload)
+	conf.ContentType = "application/octet-stream"
+
+	return conf
+}
+
+// generatedBody reports whether presetRequest replaced the body of conf with
+// generated data.
+func generatedBody(conf Schema) bool {
+	return conf.MatchType == "uploadIntegrity"
+}
+
+// uploadIntegrityMatcher expects the uploaded payload to download intact
+// from download_url, the Location of the upload, or the upload url itself,
+// in that order of preference.
+type uploadIntegrityMatcher struct{}
+
+func (uploadIntegrityMatcher) ValidateConfig(conf Schema) error {
+	if conf.Verb != http.MethodPost && conf.Verb != http.MethodPut {
+		return fmt.Errorf("uploadIntegrity requires verb POST or PUT; got: %v", conf.Verb)
+	}
+
+	if conf.UploadSize < 1 || conf.UploadSize > maxUploadSize {
+		return fmt.Errorf("upload_size must be between 1 and %d; got: %d", maxUploadSize, conf.UploadSize)
+	}
+
+	if conf.DownloadURL != "" {
+		_, err := url.Parse(conf.DownloadURL)
+		if err != nil {
+			return fmt.Errorf("invalid download_url provided: %v; %q", conf.DownloadURL, err)
+		}
+	}
+
+	return nil
+}
+
+func (uploadIntegrityMatcher) Match(ctx context.Context, ex *Exchange) error {
+	if ex.Response.StatusCode < 200 || ex.Response.StatusCode > 299 {
+		return fmt.Errorf("upload: expected 2xx status code; got: %d", ex.Response.StatusCode)
+	}
+
+	target := ex.Response.Request.URL.String()
+	if location := ex.Response.Header.Get("Location"); location != "" {
+		target = location
+	}
+	if ex.Config.DownloadURL != "" {
+		target = ex.Config.DownloadURL
+	}
+
+	req, err := ex.followUp(ctx, http.MethodGet, target)
+	if err != nil {
+		return err
+	}
+
+	resp, err := ex.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("download %s: encounted error while making request: %v", req.URL, err.Error())
+	}
+	defer drainBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download %s: expected status code: %d; got: %d", req.URL, http.StatusOK, resp.StatusCode)
+	}
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, io.LimitReader(resp.Body, maxUploadSize+1))
+	if err != nil {
+		return fmt.Errorf("download %s: encountered error while reading response body: %v", req.URL, err)
+	}
+
+	want := sha256.Sum256(ex.RequestBody)
+	got := hash.Sum(nil)
+	if !bytes.Equal(got, want[:]) {
+		return fmt.Errorf("download %s: sha256 %x does not match uploaded payload %x", req.URL, got, want)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestUploadIntegrity(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt bool
+		want    string
+	}{
+		{"intact", false, ""},
+		{"corrupted", true, "does not match uploaded payload"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var stored []byte
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if r.Method == http.MethodPut {
+					stored, _ = io.ReadAll(r.Body)
+					w.WriteHeader(http.StatusCreated)
+					return
+				}
+				if tt.corrupt && len(stored) > 0 {
+					stored[0] ^= 0xff
+				}
+				w.Write(stored)
+			}))
+			defer server.Close()
+
+			config, _ := json.Marshal(map[string]any{
+				"url":         server.URL + "/file",
+				"verb":        "PUT",
+				"match_type":  "uploadIntegrity",
+				"upload_size": 8192,
+			})
+
+			err := New().Run(context.Background(), string(config))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Run() = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Run() = %v; want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// assertVary expects the response's Vary header to name every header in
+// expect_vary. Vary: * varies on everything and satisfies any list.
+func assertVary(ctx context.Context, ex *Exchange) error {
+	if ex.Config.ExpectVary == "" {
+		return nil
+	}
+
+	vary := splitList(strings.Join(ex.Response.Header.Values("Vary"), ","))
+	if containsFold(vary, "*") {
+		return nil
+	}
+
+	missing := []string{}
+	for _, want := range splitList(ex.Config.ExpectVary) {
+		if !containsFold(vary, want) {
+			missing = append(missing, want)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("Vary %q does not include %s", strings.Join(vary, ", "), strings.Join(missing, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package http
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultVersionPattern finds the first dotted version number.
+const defaultVersionPattern = `v?\d+(?:\.\d+)+(?:-[0-9A-Za-z.-]+)?`
+
+// semver is a dotted numeric version with an optional pre-release suffix,
+// which sorts before the release itself.
+type semver struct {
+	parts      []int
+	prerelease string
+}
+
+func parseSemver(raw string) (semver, error) {
+	raw = strings.TrimPrefix(strings.TrimSpace(raw), "v")
+	raw, _, _ = strings.Cut(raw, "+")
+	core, prerelease, _ := strings.Cut(raw, "-")
+
+	v := semver{prerelease: prerelease}
+	for _, part := range strings.Split(core, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version: %q", raw)
+		}
+		v.parts = append(v.parts, n)
+	}
+
+	return v, nil
+}
+
+// compare returns -1, 0 or 1 as v is older than, equal to or newer than w.
+// Missing components count as zero, so 2.4 equals 2.4.0.
+func (v semver) compare(w semver) int {
+	for i := 0; i < max(len(v.parts), len(w.parts)); i++ {
+		a, b := 0, 0
+		if i < len(v.parts) {
+			a = v.parts[i]
+		}
+		if i < len(w.parts) {
+			b = w.parts[i]
+		}
+		if a != b {
+			if a < b {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case v.prerelease == w.prerelease:
+		return 0
+	case v.prerelease == "":
+		return 1
+	case w.prerelease == "":
+		return -1
+	default:
+		return comparePrerelease(v.prerelease, w.prerelease)
+	}
+}
+
+// comparePrerelease orders pre-release suffixes as SemVer does: identifier
+// by identifier, numeric ones by value and below alphanumeric ones, which
+// sort as ASCII. A suffix that runs out first is older, so rc.1 < rc.1.1.
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < min(len(as), len(bs)); i++ {
+		x, y := as[i], bs[i]
+		xNum, yNum := numericIdentifier(x), numericIdentifier(y)
+
+		switch {
+		case xNum && yNum:
+			// Compare by length first so long numbers cannot overflow.
+			x, y = strings.TrimLeft(x, "0"), strings.TrimLeft(y, "0")
+			if c := cmp.Compare(len(x), len(y)); c != 0 {
+				return c
+			}
+		case xNum:
+			return -1
+		case yNum:
+			return 1
+		}
+		if c := strings.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+
+	return cmp.Compare(len(as), len(bs))
+}
+
+// numericIdentifier reports whether id is all digits.
+func numericIdentifier(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// versionConstraint is an expected_output such as ">= 2.4.58". A bare
+// version means >=.
+type versionConstraint struct {
+	op   string
+	want semver
+}
+
+func parseVersionConstraint(raw string) (versionConstraint, error) {
+	raw = strings.TrimSpace(raw)
+
+	constraint := versionConstraint{op: ">="}
+	for _, op := range []string{">=", "<=", "==", "!=", ">", "<", "="} {
+		if strings.HasPrefix(raw, op) {
+			constraint.op = op
+			raw = raw[len(op):]
+			break
+		}
+	}
+	if constraint.op == "=" {
+		constraint.op = "=="
+	}
+
+	want, err := parseSemver(raw)
+	if err != nil {
+		return constraint, fmt.Errorf("expected_output must be a version constraint like \">= 2.4.58\"; %v", err)
+	}
+	constraint.want = want
+
+	return constraint, nil
+}
+
+func (c versionConstraint) allows(v semver) bool {
+	cmp := v.compare(c.want)
+
+	switch c.op {
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	default:
+		return cmp >= 0
+	}
+}
+
+// minVersionMatcher extracts a version from the response and expects it to
+// satisfy the expected_output constraint. The version comes from value_path
+// of a JSON body when set, otherwise from version_pattern matched against
+// version_header or the body; its first capture group is used if it has one.
+type minVersionMatcher struct{}
+
+func (minVersionMatcher) ValidateConfig(conf Schema) error {
+	_, err := parseVersionConstraint(conf.ExpectedOutput)
+	if err != nil {
+		return err
+	}
+
+	if conf.ValuePath != "" {
+		_, err = parsePath(conf.ValuePath)
+		return err
+	}
+
+	_, err = regexp.Compile(versionPattern(conf))
+	if err != nil {
+		return fmt.Errorf("invalid version_pattern provided: %v; %q", conf.VersionPattern, err)
+	}
+
+	return nil
+}
+
+func versionPattern(conf Schema) string {
+	if conf.VersionPattern == "" {
+		return defaultVersionPattern
+	}
+
+	return conf.VersionPattern
+}
+
+func (minVersionMatcher) Match(ctx context.Context, ex *Exchange) error {
+	constraint, err := parseVersionConstraint(ex.Config.ExpectedOutput)
+	if err != nil {
+		return err
+	}
+
+	raw, err := extractVersion(ex)
+	if err != nil {
+		return err
+	}
+
+	version, err := parseSemver(raw)
+	if err != nil {
+		return fmt.Errorf("extracted %v", err)
+	}
+
+	if !constraint.allows(version) {
+		return fmt.Errorf("version %s does not satisfy %s", raw, strings.TrimSpace(ex.Config.ExpectedOutput))
+	}
+
+	return nil
+}
+
+func extractVersion(ex *Exchange) (string, error) {
+	conf := ex.Config
+
+	if conf.ValuePath != "" {
+		body, err := readBody(ex)
+		if err != nil {
+			return "", err
+		}
+
+		doc, err := decodeJSON(body)
+		if err != nil {
+			return "", fmt.Errorf("response body is not valid json: %v", err)
+		}
+
+		value, err := lookupPath(doc, conf.ValuePath)
+		if err != nil {
+			return "", err
+		}
+
+		return scalarString(value), nil
+	}
+
+	pattern, err := regexp.Compile(versionPattern(conf))
+	if err != nil {
+		return "", fmt.Errorf("invalid version_pattern provided: %v; %q", conf.VersionPattern, err)
+	}
+
+	source := ""
+	if conf.VersionHeader != "" {
+		source = ex.Response.Header.Get(conf.VersionHeader)
+	} else {
+		body, err := readBody(ex)
+		if err != nil {
+			return "", err
+		}
+		source = string(body)
+	}
+
+	match := pattern.FindStringSubmatch(source)
+	if match == nil {
+		return "", fmt.Errorf("no version found in response")
+	}
+	if len(match) > 1 {
+		return match[1], nil
+	}
+
+	return match[0], nil
+}
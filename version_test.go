@@ -0,0 +1,118 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSemverCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2.4.58", "2.4.58", 0},
+		{"v2.4", "2.4.0", 0},
+		{"2.4.58", "2.4.9", 1},
+		{"2.4.9", "2.10", -1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0", "1.0.0-rc.1", 1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-beta.11", "1.0.0-rc.1", -1},
+		{"1.0.0-rc.10", "1.0.0-rc.9", 1},
+		{"1.0.0-1", "1.0.0-alpha", -1},
+		{"1.0.0-rc.99999999999999999999", "1.0.0-rc.100000000000000000000", -1},
+		{"1.0.0+build.5", "1.0.0", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			a, err := parseSemver(tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := parseSemver(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := a.compare(b); got != tt.want {
+				t.Errorf("compare(%q, %q) = %d; want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVersionConstraint(t *testing.T) {
+	tests := []struct {
+		raw     string
+		version string
+		allows  bool
+		err     string
+	}{
+		{"2.4.58", "2.4.58", true, ""},
+		{"2.4.58", "2.4.57", false, ""},
+		{">= 2.4.58", "2.5", true, ""},
+		{"> 2.4.58", "2.4.58", false, ""},
+		{"< 3", "2.99.1", true, ""},
+		{"<=1.2", "1.2.0", true, ""},
+		{"= 1.2", "1.2.0", true, ""},
+		{"== 1.2", "1.2.1", false, ""},
+		{"!= 1.2", "1.2.1", true, ""},
+		{">= latest", "", false, "expected_output must be a version constraint"},
+		{"", "", false, "expected_output must be a version constraint"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw+" "+tt.version, func(t *testing.T) {
+			constraint, err := parseVersionConstraint(tt.raw)
+			checkError(t, err, tt.err)
+			if tt.err != "" {
+				return
+			}
+
+			version, err := parseSemver(tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := constraint.allows(version); got != tt.allows {
+				t.Errorf("%q allows %q = %v; want %v", tt.raw, tt.version, got, tt.allows)
+			}
+		})
+	}
+}
+
+func TestMinVersionMatcher(t *testing.T) {
+	tests := []struct {
+		name string
+		conf map[string]any
+		want string
+	}{
+		{"header", map[string]any{"expected_output": ">= 2.4.58", "version_header": "Server"}, ""},
+		{"header too old", map[string]any{"expected_output": ">= 2.5", "version_header": "Server"}, "version 2.4.58 does not satisfy >= 2.5"},
+		{"body", map[string]any{"expected_output": "== 1.0.0-rc.1"}, ""},
+		{"capture group", map[string]any{"expected_output": "== 3.1", "version_pattern": `build (\d+\.\d+)`}, ""},
+		{"value_path", map[string]any{"expected_output": "< 1.0.0", "value_path": "app.version", "version_header": "Server"}, ""},
+		{"no match", map[string]any{"expected_output": "1.0", "version_pattern": `release-(\d+)`}, "no version found in response"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Server", "Apache/2.4.58 (Unix)")
+				w.Write([]byte(`{"app": {"version": "v1.0.0-rc.1+sha"}, "text": "nginx/1.26.0 build 3.1"}`))
+			}))
+			defer server.Close()
+
+			conf := map[string]any{"match_type": "minVersion"}
+			for key, value := range tt.conf {
+				conf[key] = value
+			}
+
+			err := runAgainst(t, server, conf)
+			checkError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// vhost is one line of a vhostSweep expected_output: a Host name and the
+// marker its page must contain.
+type vhost struct {
+	host   string
+	marker string
+}
+
+// parseVhosts parses lines of "host: marker"; the marker may be empty.
+func parseVhosts(raw string) ([]vhost, error) {
+	vhosts := []vhost{}
+	for _, line := range splitLines(raw) {
+		host, marker, _ := strings.Cut(line, ":")
+		host = strings.TrimSpace(host)
+		if host == "" {
+			return nil, fmt.Errorf("expected_output lines must be \"host: marker\" ; got: %v", line)
+		}
+		vhosts = append(vhosts, vhost{host: host, marker: strings.TrimSpace(marker)})
+	}
+
+	if len(vhosts) == 0 {
+		return nil, fmt.Errorf("expected_output must list at least one \"host: marker\" line")
+	}
+
+	return vhosts, nil
+}
+
+// vhostSweepMatcher sends the configured request to url once per host in
+// expected_output, with that Host header and TLS server name, and expects
+// each to succeed and contain its marker.
+type vhostSweepMatcher struct{}
+
+func (vhostSweepMatcher) ValidateConfig(conf Schema) error {
+	_, err := parseVhosts(conf.ExpectedOutput)
+	return err
+}
+
+func (vhostSweepMatcher) Match(ctx context.Context, ex *Exchange) error {
+	vhosts, err := parseVhosts(ex.Config.ExpectedOutput)
+	if err != nil {
+		return err
+	}
+
+	failures := []string{}
+	for _, vh := range vhosts {
+		err := checkVhost(ctx, ex, vh)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", vh.host, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d vhosts failed: %s", len(failures), len(vhosts), strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+func checkVhost(ctx context.Context, ex *Exchange, vh vhost) error {
+	req, err := ex.NewRequest(ctx)
+	if err != nil {
+		return err
+	}
+	req.Host = vh.host
+
+	client := ex.Client
+	if req.URL.Scheme == "https" {
+		client, err = withServerName(client, vh.host)
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("encounted error while making request: %v", err.Error())
+	}
+	defer drainBody(resp.Body)
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	if vh.marker == "" {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("encountered error while reading response body: %v", err)
+	}
+
+	if !strings.Contains(string(body), vh.marker) {
+		return fmt.Errorf("marker %q not found in response body", vh.marker)
+	}
+
+	return nil
+}
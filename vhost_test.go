@@ -0,0 +1,96 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// roundTripperFunc is a transport other than *http.Transport.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// vhostHandler serves each known Host a page naming it and the TLS server
+// name it was reached with.
+var vhostHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if r.Host == "missing.example" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	sni := ""
+	if r.TLS != nil {
+		sni = r.TLS.ServerName
+	}
+	w.Write([]byte("site " + r.Host + " sni " + sni))
+})
+
+func TestVhostSweep(t *testing.T) {
+	server := httptest.NewServer(vhostHandler)
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		vhosts string
+		want   string
+	}{
+		{"all served", "a.example: site a.example\nb.example: site b.example", ""},
+		{"no marker", "a.example:", ""},
+		{"wrong page", "a.example: site a.example\nb.example: site a.example", "1 of 2 vhosts failed: b.example: marker"},
+		{"not found", "missing.example: site", "missing.example: status 404"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runAgainst(t, server, map[string]any{
+				"match_type":      "vhostSweep",
+				"expected_output": tt.vhosts,
+			})
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestVhostSweepServerName(t *testing.T) {
+	// The test certificate covers example.com, so only the SNI makes it verify.
+	server := httptest.NewTLSServer(vhostHandler)
+	defer server.Close()
+
+	err := runAgainst(t, server, map[string]any{
+		"match_type":      "vhostSweep",
+		"expected_output": "example.com: site example.com sni example.com",
+	})
+	checkError(t, err, "")
+}
+
+func TestVhostSweepCustomTransport(t *testing.T) {
+	tests := []struct {
+		name   string
+		server *httptest.Server
+		want   string
+	}{
+		{"http", httptest.NewServer(vhostHandler), ""},
+		{"https", httptest.NewTLSServer(vhostHandler), "cannot set the TLS server name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer tt.server.Close()
+
+			transport := tt.server.Client().Transport
+			client := &http.Client{Transport: roundTripperFunc(transport.RoundTrip)}
+			config, _ := json.Marshal(map[string]any{
+				"url":             tt.server.URL,
+				"match_type":      "vhostSweep",
+				"expected_output": "a.example: site a.example",
+			})
+
+			err := New(WithClient(client)).Run(context.Background(), string(config))
+			checkError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Warning flags a config that is valid but probably not what its author
+// intended. Warnings never block a config from running.
+type Warning struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidateWithWarnings validates config like Validate and, when it is valid,
+// also returns any warnings about it.
+func ValidateWithWarnings(config string) ([]Warning, error) {
+	err := Validate(config)
+	if err != nil {
+		return nil, err
+	}
+
+	conf, err := decodeConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := []Warning{}
+	for _, lint := range lints {
+		warnings = append(warnings, lint(conf)...)
+	}
+
+	return warnings, nil
+}
+
+var lints = []func(conf Schema) []Warning{
+	lintInsecure,
+	lintRegex,
+	lintRedirectStatus,
+	lintCleartextAuth,
+}
+
+func lintInsecure(conf Schema) []Warning {
+	if !conf.Insecure {
+		return nil
+	}
+
+	usesTLS := func(raw string) bool { return !isPlainHTTP(raw) }
+	if !slices.ContainsFunc(targets(conf), usesTLS) {
+		return []Warning{{Field: "insecure", Message: "insecure has no effect on a plain http url"}}
+	}
+
+	return []Warning{{Field: "insecure", Message: "certificate verification is disabled; invalid or expired certificates will pass"}}
+}
+
+func lintRegex(conf Schema) []Warning {
+	if conf.MatchType != "regexMatch" {
+		return nil
+	}
+
+	pattern, err := regexp.Compile(conf.ExpectedOutput)
+	if err != nil {
+		return []Warning{{Field: "expected_output", Message: "regex does not compile: " + err.Error()}}
+	}
+
+	if pattern.MatchString("") {
+		return []Warning{{Field: "expected_output", Message: "regex matches an empty body, so every response will pass"}}
+	}
+
+	return nil
+}
+
+func lintRedirectStatus(conf Schema) []Warning {
+	if conf.MatchType == "statusCode" && strings.HasPrefix(conf.ExpectedOutput, "3") && conf.MaxRedirects > 0 {
+		return []Warning{{Field: "expected_output", Message: "redirects are followed, so a 3xx status code is only seen when the redirect cannot be followed; set max_redirects to 0 to check the redirect itself"}}
+	}
+
+	return nil
+}
+
+func lintCleartextAuth(conf Schema) []Warning {
+	if conf.Auth == "none" || !slices.ContainsFunc(targets(conf), isPlainHTTP) {
+		return nil
+	}
+
+	return []Warning{{Field: "auth", Message: "credentials are sent over plain http"}}
+}
+
+func isPlainHTTP(raw string) bool {
+	target, err := url.Parse(raw)
+	return err == nil && target.Scheme == "http"
+}
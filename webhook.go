@@ -0,0 +1,163 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxCallbackBody bounds how much of each callback request is kept.
+const maxCallbackBody = 1 << 20
+
+// callback is one request the target made to the webhook listener.
+type callback struct {
+	method string
+	body   []byte
+}
+
+type callbackKey struct{}
+
+// callbackListener receives the target's callbacks for one check.
+type callbackListener struct {
+	url       string
+	callbacks chan callback
+}
+
+// listenCallback starts a listener on callback_listen for a webhook check
+// and attaches its URL to ctx as the callback_url target value, so the
+// trigger request can pass it on with {{.callback_url}} in its body or
+// headers. The path is random, so only the request that was given the URL can
+// answer it. The returned stop function shuts the listener down.
+func listenCallback(ctx context.Context, conf Schema) (context.Context, func(), error) {
+	listener, err := net.Listen("tcp", conf.CallbackListen)
+	if err != nil {
+		return ctx, nil, fmt.Errorf("encountered error while starting callback listener: %v", err)
+	}
+
+	host := conf.CallbackHost
+	if host == "" {
+		host, err = sourceAddress(ctx, targets(conf)[0])
+		if err != nil {
+			listener.Close()
+			return ctx, nil, err
+		}
+	}
+
+	token, err := nonce()
+	if err != nil {
+		listener.Close()
+		return ctx, nil, err
+	}
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	receiver := &callbackListener{
+		url:       "http://" + net.JoinHostPort(host, port) + "/" + token,
+		callbacks: make(chan callback, 16),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+token, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(io.LimitReader(r.Body, maxCallbackBody))
+
+		select {
+		case receiver.callbacks <- callback{method: r.Method, body: body}:
+		default:
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+
+	stop := func() {
+		server.Close()
+	}
+
+	ctx = context.WithValue(ctx, callbackKey{}, receiver)
+	ctx = WithTarget(ctx, map[string]string{"callback_url": receiver.url})
+
+	return ctx, stop, nil
+}
+
+// sourceAddress returns the local address the host would use to reach
+// target, the best guess at an address the target can call back to. No
+// packets are sent.
+func sourceAddress(ctx context.Context, target string) (string, error) {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("callback_host must be provided when url has no host; got: %v", target)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", hostPort(u))
+	if err != nil {
+		return "", fmt.Errorf("callback_host must be provided; could not find a local address to reach %s: %v", u.Host, err)
+	}
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).IP.String(), nil
+}
+
+// webhookMatcher expects the trigger request to succeed and the target to
+// then call callback_url within callback_timeout_ms with a body containing
+// expected_output, if set.
+type webhookMatcher struct{}
+
+func (webhookMatcher) ValidateConfig(conf Schema) error {
+	_, _, err := net.SplitHostPort(conf.CallbackListen)
+	if err != nil {
+		return fmt.Errorf("invalid callback_listen provided: %v; %q", conf.CallbackListen, err)
+	}
+
+	if conf.CallbackTimeoutMs < 1 {
+		return fmt.Errorf("callback_timeout_ms must be at least 1; got: %d", conf.CallbackTimeoutMs)
+	}
+
+	if !strings.Contains(conf.Body+conf.Headers+conf.RequestHeaders, "callback_url") {
+		return fmt.Errorf("webhook requires {{.callback_url}} in body or headers so the target learns where to call back")
+	}
+
+	return nil
+}
+
+func (webhookMatcher) Match(ctx context.Context, ex *Exchange) error {
+	if ex.Response.StatusCode < 200 || ex.Response.StatusCode > 299 {
+		return fmt.Errorf("expected 2xx status code for webhook trigger; got: %d", ex.Response.StatusCode)
+	}
+
+	receiver, ok := ctx.Value(callbackKey{}).(*callbackListener)
+	if !ok {
+		return fmt.Errorf("webhook callback listener is not running")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(ex.Config.CallbackTimeoutMs)*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	received := 0
+	for {
+		select {
+		case got := <-receiver.callbacks:
+			received++
+			if strings.Contains(string(got.body), ex.Config.ExpectedOutput) {
+				ex.Result.Note("callback %s received after %s", got.method, time.Since(start).Round(time.Millisecond))
+				return nil
+			}
+		case <-ctx.Done():
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return ctx.Err()
+			}
+			if received > 0 {
+				return fmt.Errorf("received %d callbacks within %dms, none containing expected output", received, ex.Config.CallbackTimeoutMs)
+			}
+			return fmt.Errorf("no callback received within %dms", ex.Config.CallbackTimeoutMs)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// webhookServer accepts a trigger whose body is the callback URL and then
+// posts payload to it, unless payload is empty. It answers the trigger with
+// status.
+func webhookServer(status int, payload string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target, _ := io.ReadAll(r.Body)
+		if payload != "" {
+			go func() {
+				resp, err := http.Post(string(target), "text/plain", strings.NewReader(payload))
+				if err == nil {
+					resp.Body.Close()
+				}
+			}()
+		}
+		w.WriteHeader(status)
+	}))
+}
+
+func TestWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		payload string
+		conf    map[string]any
+		want    string
+	}{
+		{"called back", http.StatusAccepted, "event=done", map[string]any{"expected_output": "done"}, ""},
+		{"any callback", http.StatusOK, "ping", nil, ""},
+		{"wrong payload", http.StatusOK, "event=failed", map[string]any{"expected_output": "done"}, "received 1 callbacks within 300ms, none containing expected output"},
+		{"never called", http.StatusOK, "", nil, "no callback received within 300ms"},
+		{"trigger fails", http.StatusInternalServerError, "event=done", nil, "expected 2xx status code for webhook trigger; got: 500"},
+		{"url not passed on", http.StatusOK, "", map[string]any{"body": "hello"}, "webhook requires {{.callback_url}} in body or headers"},
+		{"bad listen address", http.StatusOK, "", map[string]any{"callback_listen": "localhost"}, "invalid callback_listen provided: localhost"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := webhookServer(tt.status, tt.payload)
+			defer server.Close()
+
+			conf := map[string]any{
+				"verb":                "POST",
+				"content_type":        "plain/text",
+				"body":                "{{.callback_url}}",
+				"match_type":          "webhook",
+				"callback_listen":     "127.0.0.1:0",
+				"callback_host":       "127.0.0.1",
+				"callback_timeout_ms": 300,
+			}
+			for key, value := range tt.conf {
+				conf[key] = value
+			}
+
+			err := runAgainst(t, server, conf)
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestWebhookNote(t *testing.T) {
+	server := webhookServer(http.StatusOK, "event=done")
+	defer server.Close()
+
+	config, _ := json.Marshal(map[string]any{
+		"url":                 server.URL,
+		"verb":                "POST",
+		"content_type":        "plain/text",
+		"body":                "{{.callback_url}}",
+		"match_type":          "webhook",
+		"callback_listen":     "127.0.0.1:0",
+		"callback_host":       "127.0.0.1",
+		"callback_timeout_ms": 1000,
+	})
+
+	res, err := New(WithClient(server.Client())).Check(context.Background(), string(config))
+	if err != nil {
+		t.Fatalf("Check() = %v; want nil", err)
+	}
+	if !slices.ContainsFunc(res.Notes, func(note string) bool {
+		return strings.HasPrefix(note, "callback POST received after")
+	}) {
+		t.Errorf("Notes = %q; want the callback noted", res.Notes)
+	}
+}
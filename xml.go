@@ -0,0 +1,71 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// validXMLMatcher expects the body to be a well-formed XML document with a
+// single root element.
+type validXMLMatcher struct{}
+
+func (validXMLMatcher) ValidateConfig(conf Schema) error {
+	return nil
+}
+
+func (validXMLMatcher) Match(ctx context.Context, ex *Exchange) error {
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	_, err = checkXML(body)
+	return err
+}
+
+// checkXML verifies body is well-formed XML with exactly one root element and
+// returns the root's local name.
+func checkXML(body []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = true
+
+	root := ""
+	depth := 0
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("response body is not valid xml: %v", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				if root != "" {
+					return "", fmt.Errorf("response body is not valid xml: more than one root element")
+				}
+				root = t.Name.Local
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && strings.TrimSpace(string(t)) != "" {
+				return "", fmt.Errorf("response body is not valid xml: text outside the root element")
+			}
+		}
+	}
+
+	if root == "" {
+		return "", fmt.Errorf("response body is not valid xml: no root element")
+	}
+
+	return root, nil
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlPathMatcher parses the body as YAML and expects value_path to exist
+// and, when expected_output is set, to equal it.
+type yamlPathMatcher struct{}
+
+func (yamlPathMatcher) ValidateConfig(conf Schema) error {
+	_, err := parsePath(conf.ValuePath)
+	return err
+}
+
+func (yamlPathMatcher) Match(ctx context.Context, ex *Exchange) error {
+	body, err := readBody(ex)
+	if err != nil {
+		return err
+	}
+
+	var doc any
+
+	err = yaml.Unmarshal(body, &doc)
+	if err != nil {
+		return fmt.Errorf("response body is not valid yaml: %v", err)
+	}
+
+	value, err := lookupPath(doc, ex.Config.ValuePath)
+	if err != nil {
+		return err
+	}
+
+	if ex.Config.ExpectedOutput != "" && scalarString(value) != ex.Config.ExpectedOutput {
+		return fmt.Errorf("expected %s to be %q; got: %q", ex.Config.ValuePath, ex.Config.ExpectedOutput, scalarString(value))
+	}
+
+	return nil
+}
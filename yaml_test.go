@@ -0,0 +1,58 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestYAMLPathMatcher(t *testing.T) {
+	body := `spec:
+  replicas: 3
+  ports:
+    80: http
+    443: https
+  limits:
+    memory: 1e6
+  released: 2024-01-02
+containers:
+  - image: nginx:1.26
+`
+
+	tests := []struct {
+		path     string
+		expected string
+		want     string
+	}{
+		{"spec.replicas", "3", ""},
+		{"containers[0].image", "nginx:1.26", ""},
+		{"spec.ports.443", "https", ""},
+		{"spec.limits.memory", "1000000", ""},
+		{"spec.released", "2024-01-02", ""},
+		{"spec.ports", `{"443":"https","80":"http"}`, ""},
+		{"spec.ports.8080", "", `no key "8080" at step 3`},
+		{"spec.replicas", "4", `expected spec.replicas to be "4"; got: "3"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			err := runAgainst(t, server, map[string]any{"match_type": "yamlPath", "value_path": tt.path, "expected_output": tt.expected})
+			checkError(t, err, tt.want)
+		})
+	}
+}
+
+func TestYAMLPathInvalidBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("a: [1, 2"))
+	}))
+	defer server.Close()
+
+	err := runAgainst(t, server, map[string]any{"match_type": "yamlPath", "value_path": "a"})
+	checkError(t, err, "response body is not valid yaml")
+}